@@ -0,0 +1,277 @@
+// Package backup serializes the contents of the gator database to a
+// gzip-compressed JSON archive and restores it into an empty database.
+package backup
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/Shubham-Hazra/blog-aggregator/internal/database"
+)
+
+// Version is the archive format written by this package. It is bumped
+// whenever the layout of Archive changes incompatibly.
+const Version = 1
+
+// Archive holds every table needed to rebuild a database. Sessions and
+// WebSub subscriptions are left out on purpose: logins don't survive a
+// restore and subscriptions are renegotiated with the hubs.
+type Archive struct {
+	Version        int             `json:"version"`
+	CreatedAt      time.Time       `json:"created_at"`
+	Users          []User          `json:"users"`
+	Feeds          []Feed          `json:"feeds"`
+	FeedFollows    []FeedFollow    `json:"feed_follows"`
+	Posts          []Post          `json:"posts"`
+	Categories     []Category      `json:"categories"`
+	PostCategories []PostCategory  `json:"post_categories"`
+	PostEnclosures []PostEnclosure `json:"post_enclosures"`
+	Downloads      []Download      `json:"downloads"`
+}
+
+// Dump reads every table into an Archive. It should be called with
+// queries bound to a transaction so the snapshot is consistent.
+func Dump(ctx context.Context, q database.Querier) (*Archive, error) {
+	a := &Archive{
+		Version:   Version,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	users, err := q.GetUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading users: %w", err)
+	}
+	a.Users = fromRows(users, fromUser)
+
+	feeds, err := q.ListAllFeeds(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading feeds: %w", err)
+	}
+	a.Feeds = fromRows(feeds, fromFeed)
+
+	follows, err := q.ListAllFeedFollows(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading feed follows: %w", err)
+	}
+	a.FeedFollows = fromRows(follows, fromFeedFollow)
+
+	posts, err := q.ListAllPosts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading posts: %w", err)
+	}
+	a.Posts = fromRows(posts, fromPost)
+
+	categories, err := q.ListAllCategories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading categories: %w", err)
+	}
+	a.Categories = fromRows(categories, fromCategory)
+
+	postCategories, err := q.ListAllPostCategories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading post categories: %w", err)
+	}
+	a.PostCategories = fromRows(postCategories, fromPostCategory)
+
+	enclosures, err := q.ListAllPostEnclosures(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading post enclosures: %w", err)
+	}
+	a.PostEnclosures = fromRows(enclosures, fromPostEnclosure)
+
+	downloads, err := q.ListAllDownloads(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading downloads: %w", err)
+	}
+	a.Downloads = fromRows(downloads, fromDownload)
+	return a, nil
+}
+
+// Write encodes the archive as gzip-compressed JSON.
+func Write(w io.Writer, a *Archive) error {
+	zw := gzip.NewWriter(w)
+	if err := json.NewEncoder(zw).Encode(a); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// WriteFile writes the archive to path. The file is only readable by
+// its owner since it contains password hashes.
+func WriteFile(path string, a *Archive) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if err := Write(f, a); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Read decodes an archive written by Write.
+func Read(r io.Reader) (*Archive, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a gator backup: %w", err)
+	}
+	defer zr.Close()
+
+	var a Archive
+	if err := json.NewDecoder(zr).Decode(&a); err != nil {
+		return nil, fmt.Errorf("not a gator backup: %w", err)
+	}
+	if a.Version < 1 || a.Version > Version {
+		return nil, fmt.Errorf("unsupported backup version %d, this build reads up to version %d", a.Version, Version)
+	}
+	return &a, nil
+}
+
+// ReadFile reads the archive stored at path.
+func ReadFile(path string) (*Archive, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// ErrNotEmpty is returned by Restore when the database already holds data.
+var ErrNotEmpty = errors.New("database is not empty")
+
+// Restore inserts the archive into an empty database, keeping all ids,
+// and moves the id sequences past the restored rows. It should be
+// called with queries bound to a transaction so a failed restore leaves
+// nothing behind.
+func Restore(ctx context.Context, q database.Querier, a *Archive) error {
+	hasData, err := q.DatabaseHasData(ctx)
+	if err != nil {
+		return err
+	}
+	if hasData {
+		return ErrNotEmpty
+	}
+
+	for _, u := range a.Users {
+		err := q.RestoreUser(ctx, database.RestoreUserParams{
+			ID:            u.ID,
+			CreatedAt:     toNullTime(u.CreatedAt),
+			UpdatedAt:     toNullTime(u.UpdatedAt),
+			Name:          u.Name,
+			PasswordHash:  toNullString(u.PasswordHash),
+			Role:          u.Role,
+			DeactivatedAt: toNullTime(u.DeactivatedAt),
+		})
+		if err != nil {
+			return fmt.Errorf("restoring user %s: %w", u.Name, err)
+		}
+	}
+	for _, f := range a.Feeds {
+		err := q.RestoreFeed(ctx, database.RestoreFeedParams{
+			ID:                f.ID,
+			Name:              f.Name,
+			Url:               f.Url,
+			UserID:            f.UserID,
+			CreatedAt:         toNullTime(f.CreatedAt),
+			UpdatedAt:         toNullTime(f.UpdatedAt),
+			LastFetchedAt:     toNullTime(f.LastFetchedAt),
+			DisabledAt:        toNullTime(f.DisabledAt),
+			DisabledReason:    toNullString(f.DisabledReason),
+			NextFetchAt:       toNullTime(f.NextFetchAt),
+			MinRefreshSeconds: toNullInt32(f.MinRefreshSeconds),
+			SkipHours:         f.SkipHours,
+			SkipDays:          f.SkipDays,
+		})
+		if err != nil {
+			return fmt.Errorf("restoring feed %s: %w", f.Url, err)
+		}
+	}
+	for _, ff := range a.FeedFollows {
+		err := q.RestoreFeedFollow(ctx, database.RestoreFeedFollowParams{
+			ID:        ff.ID,
+			CreatedAt: toNullTime(ff.CreatedAt),
+			UpdatedAt: toNullTime(ff.UpdatedAt),
+			UserID:    ff.UserID,
+			FeedID:    ff.FeedID,
+		})
+		if err != nil {
+			return fmt.Errorf("restoring feed follow %d: %w", ff.ID, err)
+		}
+	}
+	for _, p := range a.Posts {
+		err := q.RestorePost(ctx, database.RestorePostParams{
+			ID:          p.ID,
+			CreatedAt:   p.CreatedAt,
+			UpdatedAt:   p.UpdatedAt,
+			Title:       p.Title,
+			Url:         p.Url,
+			Description: toNullString(p.Description),
+			PublishedAt: toNullTime(p.PublishedAt),
+			FeedID:      p.FeedID,
+			Author:      toNullString(p.Author),
+		})
+		if err != nil {
+			return fmt.Errorf("restoring post %s: %w", p.Url, err)
+		}
+	}
+	for _, c := range a.Categories {
+		err := q.RestoreCategory(ctx, database.RestoreCategoryParams{
+			ID:   c.ID,
+			Name: c.Name,
+		})
+		if err != nil {
+			return fmt.Errorf("restoring category %s: %w", c.Name, err)
+		}
+	}
+	for _, pc := range a.PostCategories {
+		err := q.RestorePostCategory(ctx, database.RestorePostCategoryParams{
+			PostID:     pc.PostID,
+			CategoryID: pc.CategoryID,
+		})
+		if err != nil {
+			return fmt.Errorf("restoring post category: %w", err)
+		}
+	}
+	for _, e := range a.PostEnclosures {
+		err := q.RestorePostEnclosure(ctx, database.RestorePostEnclosureParams{
+			ID:              e.ID,
+			CreatedAt:       e.CreatedAt,
+			PostID:          e.PostID,
+			Url:             e.Url,
+			Length:          toNullInt64(e.Length),
+			MimeType:        toNullString(e.MimeType),
+			DurationSeconds: toNullInt32(e.DurationSeconds),
+			Episode:         toNullInt32(e.Episode),
+			Season:          toNullInt32(e.Season),
+			ImageUrl:        toNullString(e.ImageUrl),
+		})
+		if err != nil {
+			return fmt.Errorf("restoring enclosure %s: %w", e.Url, err)
+		}
+	}
+	for _, d := range a.Downloads {
+		err := q.RestoreDownload(ctx, database.RestoreDownloadParams{
+			ID:          d.ID,
+			CreatedAt:   d.CreatedAt,
+			UpdatedAt:   d.UpdatedAt,
+			UserID:      d.UserID,
+			EnclosureID: d.EnclosureID,
+			Path:        d.Path,
+			Size:        d.Size,
+			Sha256:      d.Sha256,
+		})
+		if err != nil {
+			return fmt.Errorf("restoring download %s: %w", d.Path, err)
+		}
+	}
+
+	return q.SyncSequences(ctx)
+}
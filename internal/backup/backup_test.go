@@ -0,0 +1,154 @@
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Shubham-Hazra/blog-aggregator/internal/database"
+	"github.com/Shubham-Hazra/blog-aggregator/internal/store"
+)
+
+func populate(t *testing.T, q database.Querier) {
+	t.Helper()
+	ctx := context.Background()
+	now := sql.NullTime{Time: time.Now().UTC().Truncate(time.Second), Valid: true}
+
+	user, err := q.CreateUser(ctx, database.CreateUserParams{
+		ID:           uuid.New(),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		Name:         "alice",
+		PasswordHash: sql.NullString{String: "hash", Valid: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	feed, err := q.CreateFeed(ctx, database.CreateFeedParams{
+		Name:      "Example",
+		Url:       "https://example.com/feed",
+		UserID:    user.ID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	post, err := q.CreatePost(ctx, database.CreatePostParams{
+		Title:       "Hello",
+		Url:         "https://example.com/1",
+		PublishedAt: now,
+		FeedID:      feed.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = q.CreatePostEnclosure(ctx, database.CreatePostEnclosureParams{
+		PostID:   post.ID,
+		Url:      "https://example.com/1.mp3",
+		Length:   sql.NullInt64{Int64: 100, Valid: true},
+		MimeType: sql.NullString{String: "audio/mpeg", Valid: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	source := store.NewMemory()
+	populate(t, source)
+
+	archive, err := Dump(ctx, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, archive); err != nil {
+		t.Fatal(err)
+	}
+	read, err := Read(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	target := store.NewMemory()
+	if err := Restore(ctx, target, read); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := Dump(ctx, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored.CreatedAt = archive.CreatedAt
+	if !reflect.DeepEqual(restored, archive) {
+		t.Errorf("restored database differs:\n got %+v\nwant %+v", restored, archive)
+	}
+
+	if err := Restore(ctx, target, read); err != ErrNotEmpty {
+		t.Errorf("restoring into a populated database: got %v, want %v", err, ErrNotEmpty)
+	}
+}
+
+// The archive is a file format, so its field names and the encoding of
+// NULL must not depend on the database models.
+func TestArchiveFormat(t *testing.T) {
+	source := store.NewMemory()
+	populate(t, source)
+	archive, err := Dump(context.Background(), source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, archive); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var raw struct {
+		Feeds          []map[string]any `json:"feeds"`
+		PostEnclosures []map[string]any `json:"post_enclosures"`
+		Downloads      []map[string]any `json:"downloads"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+
+	feed := raw.Feeds[0]
+	if feed["url"] != "https://example.com/feed" {
+		t.Errorf("feed url = %v", feed["url"])
+	}
+	if v, ok := feed["disabled_at"]; !ok || v != nil {
+		t.Errorf("disabled_at = %v (present %v), want null", v, ok)
+	}
+	if _, ok := feed["created_at"].(string); !ok {
+		t.Errorf("created_at = %v, want a timestamp", feed["created_at"])
+	}
+
+	enclosure := raw.PostEnclosures[0]
+	if enclosure["length"] != float64(100) || enclosure["mime_type"] != "audio/mpeg" {
+		t.Errorf("enclosure = %v", enclosure)
+	}
+	if v, ok := enclosure["episode"]; !ok || v != nil {
+		t.Errorf("episode = %v (present %v), want null", v, ok)
+	}
+
+	if raw.Downloads == nil {
+		t.Error("empty table encoded as null, want []")
+	}
+}
@@ -0,0 +1,251 @@
+package backup
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Shubham-Hazra/blog-aggregator/internal/database"
+)
+
+// The row types below define the archive format. They are kept apart
+// from the generated database models so that regenerating queries or
+// switching drivers can't change what a backup looks like. NULL columns
+// are pointers and encode as JSON null.
+
+type User struct {
+	ID            uuid.UUID  `json:"id"`
+	CreatedAt     *time.Time `json:"created_at"`
+	UpdatedAt     *time.Time `json:"updated_at"`
+	Name          string     `json:"name"`
+	PasswordHash  *string    `json:"password_hash"`
+	Role          string     `json:"role"`
+	DeactivatedAt *time.Time `json:"deactivated_at"`
+}
+
+type Feed struct {
+	ID                int32      `json:"id"`
+	Name              string     `json:"name"`
+	Url               string     `json:"url"`
+	UserID            uuid.UUID  `json:"user_id"`
+	CreatedAt         *time.Time `json:"created_at"`
+	UpdatedAt         *time.Time `json:"updated_at"`
+	LastFetchedAt     *time.Time `json:"last_fetched_at"`
+	DisabledAt        *time.Time `json:"disabled_at"`
+	DisabledReason    *string    `json:"disabled_reason"`
+	NextFetchAt       *time.Time `json:"next_fetch_at"`
+	MinRefreshSeconds *int32     `json:"min_refresh_seconds"`
+	SkipHours         int32      `json:"skip_hours"`
+	SkipDays          int32      `json:"skip_days"`
+}
+
+type FeedFollow struct {
+	ID        int32      `json:"id"`
+	CreatedAt *time.Time `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+	UserID    uuid.UUID  `json:"user_id"`
+	FeedID    int32      `json:"feed_id"`
+}
+
+type Post struct {
+	ID          int32      `json:"id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Title       string     `json:"title"`
+	Url         string     `json:"url"`
+	Description *string    `json:"description"`
+	PublishedAt *time.Time `json:"published_at"`
+	FeedID      int32      `json:"feed_id"`
+	Author      *string    `json:"author"`
+}
+
+type Category struct {
+	ID   int32  `json:"id"`
+	Name string `json:"name"`
+}
+
+type PostCategory struct {
+	PostID     int32 `json:"post_id"`
+	CategoryID int32 `json:"category_id"`
+}
+
+type PostEnclosure struct {
+	ID              int32     `json:"id"`
+	CreatedAt       time.Time `json:"created_at"`
+	PostID          int32     `json:"post_id"`
+	Url             string    `json:"url"`
+	Length          *int64    `json:"length"`
+	MimeType        *string   `json:"mime_type"`
+	DurationSeconds *int32    `json:"duration_seconds"`
+	Episode         *int32    `json:"episode"`
+	Season          *int32    `json:"season"`
+	ImageUrl        *string   `json:"image_url"`
+}
+
+type Download struct {
+	ID          int32     `json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	UserID      uuid.UUID `json:"user_id"`
+	EnclosureID int32     `json:"enclosure_id"`
+	Path        string    `json:"path"`
+	Size        int64     `json:"size"`
+	Sha256      string    `json:"sha256"`
+}
+
+func fromUser(u database.User) User {
+	return User{
+		ID:            u.ID,
+		CreatedAt:     fromNullTime(u.CreatedAt),
+		UpdatedAt:     fromNullTime(u.UpdatedAt),
+		Name:          u.Name,
+		PasswordHash:  fromNullString(u.PasswordHash),
+		Role:          u.Role,
+		DeactivatedAt: fromNullTime(u.DeactivatedAt),
+	}
+}
+
+func fromFeed(f database.Feed) Feed {
+	return Feed{
+		ID:                f.ID,
+		Name:              f.Name,
+		Url:               f.Url,
+		UserID:            f.UserID,
+		CreatedAt:         fromNullTime(f.CreatedAt),
+		UpdatedAt:         fromNullTime(f.UpdatedAt),
+		LastFetchedAt:     fromNullTime(f.LastFetchedAt),
+		DisabledAt:        fromNullTime(f.DisabledAt),
+		DisabledReason:    fromNullString(f.DisabledReason),
+		NextFetchAt:       fromNullTime(f.NextFetchAt),
+		MinRefreshSeconds: fromNullInt32(f.MinRefreshSeconds),
+		SkipHours:         f.SkipHours,
+		SkipDays:          f.SkipDays,
+	}
+}
+
+func fromFeedFollow(ff database.FeedFollow) FeedFollow {
+	return FeedFollow{
+		ID:        ff.ID,
+		CreatedAt: fromNullTime(ff.CreatedAt),
+		UpdatedAt: fromNullTime(ff.UpdatedAt),
+		UserID:    ff.UserID,
+		FeedID:    ff.FeedID,
+	}
+}
+
+func fromPost(p database.Post) Post {
+	return Post{
+		ID:          p.ID,
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+		Title:       p.Title,
+		Url:         p.Url,
+		Description: fromNullString(p.Description),
+		PublishedAt: fromNullTime(p.PublishedAt),
+		FeedID:      p.FeedID,
+		Author:      fromNullString(p.Author),
+	}
+}
+
+func fromCategory(c database.Category) Category {
+	return Category{ID: c.ID, Name: c.Name}
+}
+
+func fromPostCategory(pc database.PostCategory) PostCategory {
+	return PostCategory{PostID: pc.PostID, CategoryID: pc.CategoryID}
+}
+
+func fromPostEnclosure(e database.PostEnclosure) PostEnclosure {
+	return PostEnclosure{
+		ID:              e.ID,
+		CreatedAt:       e.CreatedAt,
+		PostID:          e.PostID,
+		Url:             e.Url,
+		Length:          fromNullInt64(e.Length),
+		MimeType:        fromNullString(e.MimeType),
+		DurationSeconds: fromNullInt32(e.DurationSeconds),
+		Episode:         fromNullInt32(e.Episode),
+		Season:          fromNullInt32(e.Season),
+		ImageUrl:        fromNullString(e.ImageUrl),
+	}
+}
+
+func fromDownload(d database.Download) Download {
+	return Download{
+		ID:          d.ID,
+		CreatedAt:   d.CreatedAt,
+		UpdatedAt:   d.UpdatedAt,
+		UserID:      d.UserID,
+		EnclosureID: d.EnclosureID,
+		Path:        d.Path,
+		Size:        d.Size,
+		Sha256:      d.Sha256,
+	}
+}
+
+// fromRows converts a slice of database rows with from. It never returns
+// nil so empty tables encode as [] rather than null.
+func fromRows[T, R any](rows []T, from func(T) R) []R {
+	out := make([]R, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, from(row))
+	}
+	return out
+}
+
+func fromNullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func fromNullString(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
+
+func fromNullInt32(n sql.NullInt32) *int32 {
+	if !n.Valid {
+		return nil
+	}
+	return &n.Int32
+}
+
+func fromNullInt64(n sql.NullInt64) *int64 {
+	if !n.Valid {
+		return nil
+	}
+	return &n.Int64
+}
+
+func toNullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
+func toNullString(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *s, Valid: true}
+}
+
+func toNullInt32(n *int32) sql.NullInt32 {
+	if n == nil {
+		return sql.NullInt32{}
+	}
+	return sql.NullInt32{Int32: *n, Valid: true}
+}
+
+func toNullInt64(n *int64) sql.NullInt64 {
+	if n == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *n, Valid: true}
+}
@@ -2,53 +2,152 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"io/fs"
 	"os"
 	"path"
 )
 
-const GATOR_CONFIG_FILE = ".gatorconfig.json" 
+const GATOR_CONFIG_FILE = ".gatorconfig.json"
+
+// Environment variables that take precedence over the config file.
+const (
+	ENV_DB_URL = "GATOR_DB_URL"
+	ENV_USER   = "GATOR_USER"
+)
 
 type Config struct {
-	DB_URL            string `json:"db_url"`
-	CURRENT_USER_NAME string `json:"current_user_name"`
+	DB_URL        string `json:"db_url"`
+	SESSION_TOKEN string `json:"session_token,omitempty"`
+	DOWNLOAD_DIR  string `json:"download_dir,omitempty"`
+	RECORD_DIR    string `json:"record_dir,omitempty"`
+	REPLAY_DIR    string `json:"replay_dir,omitempty"`
+
+	// USER_NAME is set from GATOR_USER and acts as that user without
+	// logging in. It is never saved.
+	USER_NAME string `json:"-"`
+
+	path string
 }
 
-func (c *Config) Read() {
+// Read loads the config file at configPath, or at DefaultPath when
+// configPath is empty, and applies the environment overrides. A missing
+// default file is not an error, so gator can be configured entirely
+// through the environment.
+func Read(configPath string) (*Config, error) {
+	explicit := configPath != ""
+	if !explicit {
+		var err error
+		configPath, err = DefaultPath()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c := &Config{path: configPath}
+	data, err := os.ReadFile(configPath)
+	switch {
+	case errors.Is(err, fs.ErrNotExist) && !explicit:
+	case err != nil:
+		return nil, fmt.Errorf("reading config file: %w", err)
+	default:
+		if err := json.Unmarshal(data, c); err != nil {
+			return nil, fmt.Errorf("reading config file %s: %w", configPath, err)
+		}
+	}
+
+	if dbURL := os.Getenv(ENV_DB_URL); dbURL != "" {
+		c.DB_URL = dbURL
+	}
+	c.USER_NAME = os.Getenv(ENV_USER)
+	return c, nil
+}
+
+// DefaultPath returns where the config file is looked for. The first
+// existing file of $XDG_CONFIG_HOME/gator/config.json (~/.config when
+// XDG_CONFIG_HOME is unset) and ~/.gatorconfig.json wins. If neither
+// exists, the XDG location is used when XDG_CONFIG_HOME is set, and
+// ~/.gatorconfig.json otherwise.
+func DefaultPath() (string, error) {
 	homedir, err := os.UserHomeDir()
 	if err != nil {
-		log.Fatal("Encountered an error: ", err)
+		return "", err
 	}
-	config_path := path.Join(homedir, GATOR_CONFIG_FILE)
-	data, err := os.ReadFile(config_path)
-	if err != nil {
-		log.Fatal("Error reading config file: ", err)
+	legacyPath := path.Join(homedir, GATOR_CONFIG_FILE)
+
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	xdgPath := path.Join(homedir, ".config", "gator", "config.json")
+	if configHome != "" {
+		xdgPath = path.Join(configHome, "gator", "config.json")
 	}
-	err = json.Unmarshal(data, c)
-	if err != nil {
-		log.Fatal("Error reading config file: ", err)
+
+	for _, candidate := range []string{xdgPath, legacyPath} {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
 	}
+	if configHome != "" {
+		return xdgPath, nil
+	}
+	return legacyPath, nil
 }
 
-func (c *Config) SetUser(username string) error {
-    c.CURRENT_USER_NAME = username
+// Path returns the file the config was read from, or will be saved to.
+func (c *Config) Path() string {
+	return c.path
+}
 
+// DownloadDir returns the directory media downloads are stored in,
+// defaulting to ~/gator-downloads when download_dir is not configured.
+func (c *Config) DownloadDir() (string, error) {
+	if c.DOWNLOAD_DIR != "" {
+		return c.DOWNLOAD_DIR, nil
+	}
 	homedir, err := os.UserHomeDir()
 	if err != nil {
-		log.Fatal("Encountered an error: ", err)
+		return "", err
+	}
+	return path.Join(homedir, "gator-downloads"), nil
+}
+
+// SetSession stores the session token of the logged in user. The raw
+// token is only ever kept here, the database stores its hash.
+func (c *Config) SetSession(token string) error {
+	c.SESSION_TOKEN = token
+
+	// Only the token changes in the file. Everything else is written
+	// back as read, so values from the environment don't end up in it.
+	fields := map[string]any{}
+	data, err := os.ReadFile(c.path)
+	if err == nil {
+		err = json.Unmarshal(data, &fields)
+	} else if errors.Is(err, fs.ErrNotExist) {
+		err = os.MkdirAll(path.Dir(c.path), 0700)
 	}
-	config_path := path.Join(homedir, GATOR_CONFIG_FILE)
-	
-	data, err := json.Marshal(c)
-	data = append(data, '\n')
 	if err != nil {
-		return fmt.Errorf("could not set user: %w", err)
+		return fmt.Errorf("could not save session: %w", err)
+	}
+	if token == "" {
+		delete(fields, "session_token")
+	} else {
+		fields["session_token"] = token
 	}
 
-	err = os.WriteFile(config_path, data, os.ModeExclusive)
+	data, err = json.Marshal(fields)
 	if err != nil {
-		return fmt.Errorf("could not set user: %w", err)
+		return fmt.Errorf("could not save session: %w", err)
+	}
+	data = append(data, '\n')
+
+	err = os.WriteFile(c.path, data, 0600)
+	if err != nil {
+		return fmt.Errorf("could not save session: %w", err)
+	}
+	// The file predates session tokens for most users, so tighten its
+	// permissions in case it was created world readable.
+	if err := os.Chmod(c.path, 0600); err != nil {
+		return fmt.Errorf("could not save session: %w", err)
 	}
 
 	return nil
@@ -0,0 +1,234 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// setHome points the home directory at a fresh temporary directory and
+// clears the variables that influence where and how config is read.
+func setHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv(ENV_DB_URL, "")
+	t.Setenv(ENV_USER, "")
+	return home
+}
+
+func TestDefaultPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		xdg      bool
+		existing []string
+		want     string
+	}{
+		{"nothing, no XDG_CONFIG_HOME", false, nil, ".gatorconfig.json"},
+		{"nothing, XDG_CONFIG_HOME set", true, nil, "xdg/gator/config.json"},
+		{"legacy only", true, []string{".gatorconfig.json"}, ".gatorconfig.json"},
+		{"XDG_CONFIG_HOME beats legacy", true, []string{"xdg/gator/config.json", ".gatorconfig.json"}, "xdg/gator/config.json"},
+		{"~/.config beats legacy", false, []string{".config/gator/config.json", ".gatorconfig.json"}, ".config/gator/config.json"},
+		{"~/.config ignored with XDG_CONFIG_HOME", true, []string{".config/gator/config.json"}, "xdg/gator/config.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := setHome(t)
+			if tt.xdg {
+				t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg"))
+			}
+			for _, name := range tt.existing {
+				writeFile(t, filepath.Join(home, name), "{}")
+			}
+
+			got, err := DefaultPath()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := filepath.Join(home, tt.want); got != want {
+				t.Errorf("DefaultPath() = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name       string
+		file       string // written to ~/.gatorconfig.json when not empty
+		configPath string // relative to the home directory
+		env        map[string]string
+		wantErr    bool
+		wantDBURL  string
+		wantUser   string
+	}{
+		{
+			name:      "from file",
+			file:      `{"db_url":"postgres://file"}`,
+			wantDBURL: "postgres://file",
+		},
+		{
+			name: "missing default file",
+		},
+		{
+			name:       "missing --config file",
+			configPath: "nope.json",
+			wantErr:    true,
+		},
+		{
+			name:       "explicit file",
+			file:       `{"db_url":"postgres://default"}`,
+			configPath: ".gatorconfig.json",
+			wantDBURL:  "postgres://default",
+		},
+		{
+			name:    "malformed file",
+			file:    `{"db_url":`,
+			wantErr: true,
+		},
+		{
+			name:      "environment overrides file",
+			file:      `{"db_url":"postgres://file"}`,
+			env:       map[string]string{ENV_DB_URL: "sqlite:/tmp/env.db", ENV_USER: "alice"},
+			wantDBURL: "sqlite:/tmp/env.db",
+			wantUser:  "alice",
+		},
+		{
+			name:      "environment without file",
+			env:       map[string]string{ENV_DB_URL: "sqlite:/tmp/env.db"},
+			wantDBURL: "sqlite:/tmp/env.db",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := setHome(t)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			if tt.file != "" {
+				writeFile(t, filepath.Join(home, GATOR_CONFIG_FILE), tt.file)
+			}
+			configPath := ""
+			if tt.configPath != "" {
+				configPath = filepath.Join(home, tt.configPath)
+			}
+
+			c, err := Read(configPath)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Read succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.DB_URL != tt.wantDBURL {
+				t.Errorf("DB_URL = %q, want %q", c.DB_URL, tt.wantDBURL)
+			}
+			if c.USER_NAME != tt.wantUser {
+				t.Errorf("USER_NAME = %q, want %q", c.USER_NAME, tt.wantUser)
+			}
+			if c.Path() == "" {
+				t.Error("Path() is empty")
+			}
+		})
+	}
+}
+
+func TestSetSession(t *testing.T) {
+	home := setHome(t)
+	t.Setenv(ENV_DB_URL, "sqlite:/tmp/env.db")
+	path := filepath.Join(home, GATOR_CONFIG_FILE)
+	writeFile(t, path, `{"db_url":"postgres://file","download_dir":"/media","extra":{"kept":true}}`)
+	if err := os.Chmod(path, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := Read("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetSession("token"); err != nil {
+		t.Fatal(err)
+	}
+
+	fields := readFields(t, path)
+	want := map[string]any{
+		"db_url":        "postgres://file",
+		"download_dir":  "/media",
+		"extra":         map[string]any{"kept": true},
+		"session_token": "token",
+	}
+	if len(fields) != len(want) {
+		t.Errorf("after SetSession the file holds %v, want %v", fields, want)
+	}
+	for key, value := range want {
+		if !jsonEqual(fields[key], value) {
+			t.Errorf("%s = %v, want %v", key, fields[key], value)
+		}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("config file mode = %v, want 0600", perm)
+	}
+
+	if err := c.SetSession(""); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := readFields(t, path)["session_token"]; ok {
+		t.Error("session_token still saved after logging out")
+	}
+}
+
+func TestSetSessionCreatesFile(t *testing.T) {
+	home := setHome(t)
+	xdg := filepath.Join(home, "xdg")
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+
+	c, err := Read("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetSession("token"); err != nil {
+		t.Fatal(err)
+	}
+	fields := readFields(t, filepath.Join(xdg, "gator", "config.json"))
+	if len(fields) != 1 || fields["session_token"] != "token" {
+		t.Errorf("new config file holds %v, want only the session token", fields)
+	}
+}
+
+func readFields(t *testing.T, path string) map[string]any {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := map[string]any{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("config file is not valid JSON: %v", err)
+	}
+	return fields
+}
+
+func jsonEqual(a, b any) bool {
+	x, _ := json.Marshal(a)
+	y, _ := json.Marshal(b)
+	return string(x) == string(y)
+}
@@ -12,6 +12,18 @@ import (
 	"github.com/google/uuid"
 )
 
+const countFeedsForUser = `-- name: CountFeedsForUser :one
+SELECT COUNT(*) FROM feeds
+WHERE user_id = $1
+`
+
+func (q *Queries) CountFeedsForUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countFeedsForUser, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createFeed = `-- name: CreateFeed :one
 INSERT INTO feeds (name, url, user_id, created_at, updated_at)
 VALUES (
@@ -21,7 +33,7 @@ VALUES (
     $4,
     $5
 )
-RETURNING id, name, url, user_id, created_at, updated_at, last_fetched_at
+RETURNING id, name, url, user_id, created_at, updated_at, last_fetched_at, disabled_at, disabled_reason, next_fetch_at, min_refresh_seconds, skip_hours, skip_days
 `
 
 type CreateFeedParams struct {
@@ -49,12 +61,83 @@ func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.LastFetchedAt,
+		&i.DisabledAt,
+		&i.DisabledReason,
+		&i.NextFetchAt,
+		&i.MinRefreshSeconds,
+		&i.SkipHours,
+		&i.SkipDays,
+	)
+	return i, err
+}
+
+const deleteFeed = `-- name: DeleteFeed :exec
+DELETE FROM feeds
+WHERE id = $1
+`
+
+func (q *Queries) DeleteFeed(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, deleteFeed, id)
+	return err
+}
+
+const deleteFeedsForUser = `-- name: DeleteFeedsForUser :exec
+DELETE FROM feeds
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteFeedsForUser(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteFeedsForUser, userID)
+	return err
+}
+
+const disableFeed = `-- name: DisableFeed :exec
+UPDATE feeds
+SET disabled_at = CURRENT_TIMESTAMP,
+    disabled_reason = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+`
+
+type DisableFeedParams struct {
+	ID             int32
+	DisabledReason sql.NullString
+}
+
+func (q *Queries) DisableFeed(ctx context.Context, arg DisableFeedParams) error {
+	_, err := q.db.ExecContext(ctx, disableFeed, arg.ID, arg.DisabledReason)
+	return err
+}
+
+const getFeed = `-- name: GetFeed :one
+SELECT id, name, url, user_id, created_at, updated_at, last_fetched_at, disabled_at, disabled_reason, next_fetch_at, min_refresh_seconds, skip_hours, skip_days
+FROM feeds
+WHERE id = $1
+`
+
+func (q *Queries) GetFeed(ctx context.Context, id int32) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, getFeed, id)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LastFetchedAt,
+		&i.DisabledAt,
+		&i.DisabledReason,
+		&i.NextFetchAt,
+		&i.MinRefreshSeconds,
+		&i.SkipHours,
+		&i.SkipDays,
 	)
 	return i, err
 }
 
 const getFeedFromUrl = `-- name: GetFeedFromUrl :one
-SELECT id, name, url, user_id, created_at, updated_at, last_fetched_at 
+SELECT id, name, url, user_id, created_at, updated_at, last_fetched_at, disabled_at, disabled_reason, next_fetch_at, min_refresh_seconds, skip_hours, skip_days 
 FROM feeds
 WHERE url = $1
 `
@@ -70,10 +153,34 @@ func (q *Queries) GetFeedFromUrl(ctx context.Context, url string) (Feed, error)
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.LastFetchedAt,
+		&i.DisabledAt,
+		&i.DisabledReason,
+		&i.NextFetchAt,
+		&i.MinRefreshSeconds,
+		&i.SkipHours,
+		&i.SkipDays,
 	)
 	return i, err
 }
 
+const getFeedStats = `-- name: GetFeedStats :one
+SELECT
+    (SELECT COUNT(*) FROM feed_follows WHERE feed_follows.feed_id = $1) AS follower_count,
+    (SELECT COUNT(*) FROM posts WHERE posts.feed_id = $1) AS post_count
+`
+
+type GetFeedStatsRow struct {
+	FollowerCount int64
+	PostCount     int64
+}
+
+func (q *Queries) GetFeedStats(ctx context.Context, feedID int32) (GetFeedStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, getFeedStats, feedID)
+	var i GetFeedStatsRow
+	err := row.Scan(&i.FollowerCount, &i.PostCount)
+	return i, err
+}
+
 const getFeeds = `-- name: GetFeeds :many
 SELECT 
     feeds.name AS feed_name,
@@ -116,15 +223,168 @@ func (q *Queries) GetFeeds(ctx context.Context) ([]GetFeedsRow, error) {
 	return items, nil
 }
 
-const getNextFeedToFetch = `-- name: GetNextFeedToFetch :one
-SELECT id, name, url, user_id, created_at, updated_at, last_fetched_at
+const getNextFeedsToFetch = `-- name: GetNextFeedsToFetch :many
+SELECT id, name, url, user_id, created_at, updated_at, last_fetched_at, disabled_at, disabled_reason, next_fetch_at, min_refresh_seconds, skip_hours, skip_days
 FROM feeds
-ORDER BY last_fetched_at NULLS FIRST
-LIMIT 1
+WHERE disabled_at IS NULL
+  AND (next_fetch_at IS NULL OR next_fetch_at <= CURRENT_TIMESTAMP)
+  AND (last_fetched_at IS NULL
+       OR min_refresh_seconds IS NULL
+       OR last_fetched_at + (min_refresh_seconds * INTERVAL '1 second') <= CURRENT_TIMESTAMP)
+  AND (skip_hours & $1::int) = 0
+  AND (skip_days & $2::int) = 0
+ORDER BY next_fetch_at NULLS FIRST, last_fetched_at NULLS FIRST
+LIMIT $3
+`
+
+type GetNextFeedsToFetchParams struct {
+	HourBit int32
+	DayBit  int32
+	Limit   int32
+}
+
+func (q *Queries) GetNextFeedsToFetch(ctx context.Context, arg GetNextFeedsToFetchParams) ([]Feed, error) {
+	rows, err := q.db.QueryContext(ctx, getNextFeedsToFetch, arg.HourBit, arg.DayBit, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Feed
+	for rows.Next() {
+		var i Feed
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Url,
+			&i.UserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LastFetchedAt,
+			&i.DisabledAt,
+			&i.DisabledReason,
+			&i.NextFetchAt,
+			&i.MinRefreshSeconds,
+			&i.SkipHours,
+			&i.SkipDays,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markFeedFetched = `-- name: MarkFeedFetched :exec
+UPDATE feeds
+SET last_fetched_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
 `
 
-func (q *Queries) GetNextFeedToFetch(ctx context.Context) (Feed, error) {
-	row := q.db.QueryRowContext(ctx, getNextFeedToFetch)
+func (q *Queries) MarkFeedFetched(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, markFeedFetched, id)
+	return err
+}
+
+const moveFeedFollows = `-- name: MoveFeedFollows :exec
+INSERT INTO feed_follows (created_at, updated_at, user_id, feed_id)
+SELECT created_at, CURRENT_TIMESTAMP, user_id, $1
+FROM feed_follows
+WHERE feed_id = $2
+ON CONFLICT (user_id, feed_id) DO NOTHING
+`
+
+type MoveFeedFollowsParams struct {
+	TargetFeedID int32
+	SourceFeedID int32
+}
+
+func (q *Queries) MoveFeedFollows(ctx context.Context, arg MoveFeedFollowsParams) error {
+	_, err := q.db.ExecContext(ctx, moveFeedFollows, arg.TargetFeedID, arg.SourceFeedID)
+	return err
+}
+
+const moveFeedPosts = `-- name: MoveFeedPosts :exec
+UPDATE posts
+SET feed_id = $1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE feed_id = $2
+`
+
+type MoveFeedPostsParams struct {
+	TargetFeedID int32
+	SourceFeedID int32
+}
+
+func (q *Queries) MoveFeedPosts(ctx context.Context, arg MoveFeedPostsParams) error {
+	_, err := q.db.ExecContext(ctx, moveFeedPosts, arg.TargetFeedID, arg.SourceFeedID)
+	return err
+}
+
+const notifyFeedAdded = `-- name: NotifyFeedAdded :exec
+SELECT pg_notify('gator_feed_added', $1::text)
+`
+
+func (q *Queries) NotifyFeedAdded(ctx context.Context, feedID string) error {
+	_, err := q.db.ExecContext(ctx, notifyFeedAdded, feedID)
+	return err
+}
+
+const postponeFeedFetch = `-- name: PostponeFeedFetch :exec
+UPDATE feeds
+SET next_fetch_at = CURRENT_TIMESTAMP + ($1::int * INTERVAL '1 second'),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $2
+`
+
+type PostponeFeedFetchParams struct {
+	DelaySeconds int32
+	ID           int32
+}
+
+func (q *Queries) PostponeFeedFetch(ctx context.Context, arg PostponeFeedFetchParams) error {
+	_, err := q.db.ExecContext(ctx, postponeFeedFetch, arg.DelaySeconds, arg.ID)
+	return err
+}
+
+const reassignFeeds = `-- name: ReassignFeeds :exec
+UPDATE feeds
+SET user_id = $1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE user_id = $2
+`
+
+type ReassignFeedsParams struct {
+	ToUserID   uuid.UUID
+	FromUserID uuid.UUID
+}
+
+func (q *Queries) ReassignFeeds(ctx context.Context, arg ReassignFeedsParams) error {
+	_, err := q.db.ExecContext(ctx, reassignFeeds, arg.ToUserID, arg.FromUserID)
+	return err
+}
+
+const renameFeed = `-- name: RenameFeed :one
+UPDATE feeds
+SET name = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING id, name, url, user_id, created_at, updated_at, last_fetched_at, disabled_at, disabled_reason, next_fetch_at, min_refresh_seconds, skip_hours, skip_days
+`
+
+type RenameFeedParams struct {
+	ID   int32
+	Name string
+}
+
+func (q *Queries) RenameFeed(ctx context.Context, arg RenameFeedParams) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, renameFeed, arg.ID, arg.Name)
 	var i Feed
 	err := row.Scan(
 		&i.ID,
@@ -134,18 +394,73 @@ func (q *Queries) GetNextFeedToFetch(ctx context.Context) (Feed, error) {
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.LastFetchedAt,
+		&i.DisabledAt,
+		&i.DisabledReason,
+		&i.NextFetchAt,
+		&i.MinRefreshSeconds,
+		&i.SkipHours,
+		&i.SkipDays,
 	)
 	return i, err
 }
 
-const markFeedFetched = `-- name: MarkFeedFetched :exec
+const updateFeedUrl = `-- name: UpdateFeedUrl :one
 UPDATE feeds
-SET last_fetched_at = CURRENT_TIMESTAMP,
+SET url = $2,
+    disabled_at = NULL,
+    disabled_reason = NULL,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1
+RETURNING id, name, url, user_id, created_at, updated_at, last_fetched_at, disabled_at, disabled_reason, next_fetch_at, min_refresh_seconds, skip_hours, skip_days
 `
 
-func (q *Queries) MarkFeedFetched(ctx context.Context, id int32) error {
-	_, err := q.db.ExecContext(ctx, markFeedFetched, id)
+type UpdateFeedUrlParams struct {
+	ID  int32
+	Url string
+}
+
+func (q *Queries) UpdateFeedUrl(ctx context.Context, arg UpdateFeedUrlParams) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, updateFeedUrl, arg.ID, arg.Url)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LastFetchedAt,
+		&i.DisabledAt,
+		&i.DisabledReason,
+		&i.NextFetchAt,
+		&i.MinRefreshSeconds,
+		&i.SkipHours,
+		&i.SkipDays,
+	)
+	return i, err
+}
+
+const updateFeedRefreshHints = `-- name: UpdateFeedRefreshHints :exec
+UPDATE feeds
+SET min_refresh_seconds = $2,
+    skip_hours = $3,
+    skip_days = $4
+WHERE id = $1
+`
+
+type UpdateFeedRefreshHintsParams struct {
+	ID                int32
+	MinRefreshSeconds sql.NullInt32
+	SkipHours         int32
+	SkipDays          int32
+}
+
+func (q *Queries) UpdateFeedRefreshHints(ctx context.Context, arg UpdateFeedRefreshHintsParams) error {
+	_, err := q.db.ExecContext(ctx, updateFeedRefreshHints,
+		arg.ID,
+		arg.MinRefreshSeconds,
+		arg.SkipHours,
+		arg.SkipDays,
+	)
 	return err
 }
@@ -11,14 +11,36 @@ import (
 	"github.com/google/uuid"
 )
 
+type Category struct {
+	ID   int32
+	Name string
+}
+
+type Download struct {
+	ID          int32
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	UserID      uuid.UUID
+	EnclosureID int32
+	Path        string
+	Size        int64
+	Sha256      string
+}
+
 type Feed struct {
-	ID            int32
-	Name          string
-	Url           string
-	UserID        uuid.UUID
-	CreatedAt     sql.NullTime
-	UpdatedAt     sql.NullTime
-	LastFetchedAt sql.NullTime
+	ID                int32
+	Name              string
+	Url               string
+	UserID            uuid.UUID
+	CreatedAt         sql.NullTime
+	UpdatedAt         sql.NullTime
+	LastFetchedAt     sql.NullTime
+	DisabledAt        sql.NullTime
+	DisabledReason    sql.NullString
+	NextFetchAt       sql.NullTime
+	MinRefreshSeconds sql.NullInt32
+	SkipHours         int32
+	SkipDays          int32
 }
 
 type FeedFollow struct {
@@ -38,11 +60,52 @@ type Post struct {
 	Description sql.NullString
 	PublishedAt sql.NullTime
 	FeedID      int32
+	Author      sql.NullString
+}
+
+type PostCategory struct {
+	PostID     int32
+	CategoryID int32
+}
+
+type PostEnclosure struct {
+	ID              int32
+	CreatedAt       time.Time
+	PostID          int32
+	Url             string
+	Length          sql.NullInt64
+	MimeType        sql.NullString
+	DurationSeconds sql.NullInt32
+	Episode         sql.NullInt32
+	Season          sql.NullInt32
+	ImageUrl        sql.NullString
+}
+
+type Session struct {
+	TokenHash string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UserID    uuid.UUID
 }
 
 type User struct {
-	ID        uuid.UUID
-	CreatedAt sql.NullTime
-	UpdatedAt sql.NullTime
-	Name      string
+	ID            uuid.UUID
+	CreatedAt     sql.NullTime
+	UpdatedAt     sql.NullTime
+	Name          string
+	PasswordHash  sql.NullString
+	Role          string
+	DeactivatedAt sql.NullTime
+}
+
+type WebsubSubscription struct {
+	ID             int32
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	FeedID         int32
+	HubUrl         string
+	TopicUrl       string
+	Secret         string
+	State          string
+	LeaseExpiresAt sql.NullTime
 }
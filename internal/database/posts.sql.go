@@ -13,9 +13,9 @@ import (
 	"github.com/google/uuid"
 )
 
-const createPost = `-- name: CreatePost :exec
-INSERT INTO posts (title, url, description, published_at, feed_id)
-VALUES ($1, $2, $3, $4, $5)
+const createPost = `-- name: CreatePost :one
+INSERT INTO posts (title, url, description, published_at, feed_id, author)
+VALUES ($1, $2, $3, $4, $5, $6)
 RETURNING id, created_at, updated_at
 `
 
@@ -25,32 +25,52 @@ type CreatePostParams struct {
 	Description sql.NullString
 	PublishedAt sql.NullTime
 	FeedID      int32
+	Author      sql.NullString
 }
 
-func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) error {
-	_, err := q.db.ExecContext(ctx, createPost,
+type CreatePostRow struct {
+	ID        int32
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (CreatePostRow, error) {
+	row := q.db.QueryRowContext(ctx, createPost,
 		arg.Title,
 		arg.Url,
 		arg.Description,
 		arg.PublishedAt,
 		arg.FeedID,
+		arg.Author,
 	)
-	return err
+	var i CreatePostRow
+	err := row.Scan(&i.ID, &i.CreatedAt, &i.UpdatedAt)
+	return i, err
 }
 
 const getPostsForUser = `-- name: GetPostsForUser :many
-SELECT p.id, p.created_at, p.updated_at, p.title, p.url, p.description, p.published_at, p.feed_id, f.name as feed_name
+SELECT p.id, p.created_at, p.updated_at, p.title, p.url, p.description, p.published_at, p.feed_id, p.author, f.name as feed_name
 FROM posts p
 JOIN feeds f ON p.feed_id = f.id
 JOIN feed_follows ff ON f.id = ff.feed_id
 WHERE ff.user_id = $1
+  AND ($2::text IS NULL OR p.author ILIKE '%' || $2 || '%')
+  AND ($3::text IS NULL OR EXISTS (
+    SELECT 1
+    FROM post_categories pc
+    JOIN categories c ON pc.category_id = c.id
+    WHERE pc.post_id = p.id
+      AND c.name = $3
+  ))
 ORDER BY p.published_at DESC
-LIMIT $2
+LIMIT $4
 `
 
 type GetPostsForUserParams struct {
-	UserID uuid.UUID
-	Limit  int32
+	UserID   uuid.UUID
+	Author   sql.NullString
+	Category sql.NullString
+	Limit    int32
 }
 
 type GetPostsForUserRow struct {
@@ -62,11 +82,17 @@ type GetPostsForUserRow struct {
 	Description sql.NullString
 	PublishedAt sql.NullTime
 	FeedID      int32
+	Author      sql.NullString
 	FeedName    string
 }
 
 func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams) ([]GetPostsForUserRow, error) {
-	rows, err := q.db.QueryContext(ctx, getPostsForUser, arg.UserID, arg.Limit)
+	rows, err := q.db.QueryContext(ctx, getPostsForUser,
+		arg.UserID,
+		arg.Author,
+		arg.Category,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -83,6 +109,7 @@ func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams
 			&i.Description,
 			&i.PublishedAt,
 			&i.FeedID,
+			&i.Author,
 			&i.FeedName,
 		); err != nil {
 			return nil, err
@@ -97,3 +124,40 @@ func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams
 	}
 	return items, nil
 }
+
+const getRecentPostTimesForFeed = `-- name: GetRecentPostTimesForFeed :many
+SELECT published_at
+FROM posts
+WHERE feed_id = $1
+  AND published_at IS NOT NULL
+ORDER BY published_at DESC
+LIMIT $2
+`
+
+type GetRecentPostTimesForFeedParams struct {
+	FeedID int32
+	Limit  int32
+}
+
+func (q *Queries) GetRecentPostTimesForFeed(ctx context.Context, arg GetRecentPostTimesForFeedParams) ([]sql.NullTime, error) {
+	rows, err := q.db.QueryContext(ctx, getRecentPostTimesForFeed, arg.FeedID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []sql.NullTime
+	for rows.Next() {
+		var published_at sql.NullTime
+		if err := rows.Scan(&published_at); err != nil {
+			return nil, err
+		}
+		items = append(items, published_at)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -7,10 +7,53 @@ package database
 
 import (
 	"context"
+
+	"github.com/google/uuid"
+)
+
+const deleteAllFeedFollowsForUser = `-- name: DeleteAllFeedFollowsForUser :exec
+DELETE FROM feed_follows
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteAllFeedFollowsForUser(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteAllFeedFollowsForUser, userID)
+	return err
+}
+
+const deleteAllPosts = `-- name: DeleteAllPosts :exec
+DELETE FROM posts
+`
+
+func (q *Queries) DeleteAllPosts(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteAllPosts)
+	return err
+}
+
+const deleteDownloadsForUser = `-- name: DeleteDownloadsForUser :exec
+DELETE FROM downloads
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteDownloadsForUser(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteDownloadsForUser, userID)
+	return err
+}
+
+const deleteUnusedCategories = `-- name: DeleteUnusedCategories :exec
+DELETE FROM categories
+WHERE NOT EXISTS (
+    SELECT 1 FROM post_categories WHERE post_categories.category_id = categories.id
 )
+`
+
+func (q *Queries) DeleteUnusedCategories(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteUnusedCategories)
+	return err
+}
 
 const resetTables = `-- name: ResetTables :exec
-TRUNCATE TABLE users, feeds, feed_follows CASCADE
+TRUNCATE TABLE users, feeds, feed_follows, categories CASCADE
 `
 
 func (q *Queries) ResetTables(ctx context.Context) error {
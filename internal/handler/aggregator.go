@@ -0,0 +1,560 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Shubham-Hazra/blog-aggregator/internal/database"
+	"github.com/Shubham-Hazra/blog-aggregator/pkg/hostlimit"
+	"github.com/Shubham-Hazra/blog-aggregator/pkg/markup"
+	"github.com/Shubham-Hazra/blog-aggregator/pkg/rss"
+	"github.com/Shubham-Hazra/blog-aggregator/pkg/types"
+	"github.com/lib/pq"
+)
+
+const (
+	// defaultRetryAfter is used for 429 and 503 responses without a
+	// usable Retry-After header.
+	defaultRetryAfter = 15 * time.Minute
+	maxRetryAfter     = 24 * time.Hour
+
+	// postHistorySize is the number of recent posts the polling interval
+	// of a feed is derived from.
+	postHistorySize = 20
+
+	defaultMinInterval = 10 * time.Minute
+	defaultMaxInterval = 24 * time.Hour
+
+	// feedAddedChannel is the Postgres NOTIFY channel addfeed announces
+	// new feeds on.
+	feedAddedChannel = "gator_feed_added"
+)
+
+type aggregator struct {
+	limiter     *hostlimit.Limiter
+	batchSize   int
+	minInterval time.Duration
+	maxInterval time.Duration
+	// replaying is set when feeds are served from recordings. Redirects
+	// and 410s in them are not applied: recordings are found by the URL
+	// they were made for, and the database shouldn't change for a replay.
+	replaying bool
+
+	// failures counts the consecutive failed fetches of each feed, by
+	// ID, for as long as the aggregator runs.
+	mu       sync.Mutex
+	failures map[int32]int
+}
+
+// fetchFailed records a failed fetch of feedID and returns how long to
+// wait before the next attempt.
+func (agg *aggregator) fetchFailed(feedID int32) time.Duration {
+	agg.mu.Lock()
+	defer agg.mu.Unlock()
+	if agg.failures == nil {
+		agg.failures = make(map[int32]int)
+	}
+	agg.failures[feedID]++
+	return failureBackoff(agg.failures[feedID], agg.minInterval, agg.maxInterval)
+}
+
+func (agg *aggregator) fetchSucceeded(feedID int32) {
+	agg.mu.Lock()
+	defer agg.mu.Unlock()
+	delete(agg.failures, feedID)
+}
+
+// failureBackoff is the delay after the given number of consecutive
+// failures: minInterval, doubling with every further failure, up to
+// maxInterval.
+func failureBackoff(failures int, minInterval, maxInterval time.Duration) time.Duration {
+	delay := minInterval
+	for i := 1; i < failures && delay < maxInterval; i++ {
+		delay *= 2
+	}
+	return min(delay, maxInterval)
+}
+
+// HandleAgg handles the aggregation of RSS feeds
+func HandleAgg(s *State, cmd types.Command) error {
+	flags := flag.NewFlagSet("agg", flag.ContinueOnError)
+	concurrency := flags.Int("concurrency", 4, "number of feeds fetched in parallel per tick")
+	perHost := flags.Int("per-host", 1, "maximum concurrent requests to a single host")
+	hostDelay := flags.Duration("host-delay", 2*time.Second, "minimum delay between requests to the same host")
+	minInterval := flags.Duration("min-interval", defaultMinInterval, "shortest time between fetches of one feed")
+	maxInterval := flags.Duration("max-interval", defaultMaxInterval, "longest time between fetches of one feed")
+	recordDir := flags.String("record", "", "save every feed response to this directory")
+	replayDir := flags.String("replay", "", "serve feeds from responses saved with --record instead of the network")
+	if err := flags.Parse(cmd.Args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: agg [--concurrency <n>] [--per-host <n>] [--host-delay <duration>] [--min-interval <duration>] [--max-interval <duration>] [--record <dir> | --replay <dir>] <time_between_reqs>")
+	}
+	if *minInterval > *maxInterval {
+		return fmt.Errorf("--min-interval must not exceed --max-interval")
+	}
+	// The flags take precedence over record_dir and replay_dir.
+	if *recordDir != "" || *replayDir != "" {
+		fetcher, err := newFetcher(*recordDir, *replayDir)
+		if err != nil {
+			return err
+		}
+		s.Fetcher = fetcher
+	}
+
+	time_between_reqs := flags.Arg(0)
+	timeBetweenRequests, err := time.ParseDuration(time_between_reqs)
+	if err != nil {
+		return err
+	}
+
+	_, replaying := s.Fetcher.(*rss.Replayer)
+	agg := &aggregator{
+		limiter:     hostlimit.New(*perHost, *hostDelay),
+		batchSize:   max(*concurrency, 1),
+		minInterval: *minInterval,
+		maxInterval: *maxInterval,
+		replaying:   replaying,
+	}
+
+	newFeeds := listenForNewFeeds(s)
+
+	fmt.Println("Collecting feeds every " + timeBetweenRequests.String())
+	ticker := time.NewTicker(timeBetweenRequests)
+	if err := scrapeFeeds(s, agg); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ticker.C:
+			if err := scrapeFeeds(s, agg); err != nil {
+				return err
+			}
+		case feedID := <-newFeeds:
+			scrapeNewFeed(s, agg, feedID)
+		}
+	}
+}
+
+// listenForNewFeeds subscribes to the notifications sent by addfeed. If
+// listening fails, or the database isn't Postgres, the returned channel
+// is nil, and new feeds are simply picked up by the regular schedule.
+func listenForNewFeeds(s *State) <-chan int32 {
+	if s.DBQueries.Driver() != "postgres" {
+		return nil
+	}
+
+	listener := pq.NewListener(s.Config.DB_URL, 10*time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("Feed notification listener: %v\n", err)
+		}
+	})
+	if err := listener.Listen(feedAddedChannel); err != nil {
+		log.Printf("Not listening for new feeds: %v\n", err)
+		listener.Close()
+		return nil
+	}
+
+	feedIDs := make(chan int32)
+	go func() {
+		for notification := range listener.Notify {
+			// A nil notification signals a reconnect, after which
+			// notifications may have been missed; the schedule covers them.
+			if notification == nil {
+				continue
+			}
+			id, err := strconv.Atoi(notification.Extra)
+			if err != nil {
+				log.Printf("Ignoring malformed feed notification %q\n", notification.Extra)
+				continue
+			}
+			feedIDs <- int32(id)
+		}
+	}()
+	return feedIDs
+}
+
+// scrapeNewFeed fetches a feed announced by addfeed right away.
+func scrapeNewFeed(s *State, agg *aggregator, feedID int32) {
+	feed, err := s.DBQueries.GetFeed(context.Background(), feedID)
+	if err != nil {
+		log.Printf("Error loading new feed %d: %v\n", feedID, err)
+		return
+	}
+	if feed.LastFetchedAt.Valid || feed.DisabledAt.Valid {
+		return
+	}
+
+	if err := s.DBQueries.MarkFeedFetched(context.Background(), feed.ID); err != nil {
+		log.Printf("Error marking feed %s fetched: %v\n", feed.Url, err)
+		return
+	}
+	log.Printf("Fetching newly added feed %s\n", feed.Url)
+	scrapeFeed(s, agg, feed)
+}
+
+// scrapeFeeds fetches the next batch of due feeds in parallel, subject to
+// the aggregator's per-host limits.
+func scrapeFeeds(s *State, agg *aggregator) error {
+	now := time.Now()
+	feeds, err := s.DBQueries.GetNextFeedsToFetch(context.Background(), database.GetNextFeedsToFetchParams{
+		HourBit: rss.HourBit(now),
+		DayBit:  rss.DayBit(now),
+		Limit:   int32(agg.batchSize),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, feed := range feeds {
+		err = s.DBQueries.MarkFeedFetched(context.Background(), feed.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, feed := range feeds {
+		wg.Add(1)
+		go func(feed database.Feed) {
+			defer wg.Done()
+			scrapeFeed(s, agg, feed)
+		}(feed)
+	}
+	wg.Wait()
+
+	return nil
+}
+
+func scrapeFeed(s *State, agg *aggregator, nextFeed database.Feed) {
+	host := hostlimit.Host(nextFeed.Url)
+	release, err := agg.limiter.Acquire(context.Background(), host)
+	var backoffErr *hostlimit.BackoffError
+	if errors.As(err, &backoffErr) {
+		// Leave the feed to a later tick rather than holding this one up.
+		postponeFeed(s, &nextFeed, time.Until(backoffErr.Until))
+		return
+	} else if err != nil {
+		log.Printf("Error waiting to fetch feed %s: %v\n", nextFeed.Url, err)
+		postponeFeed(s, &nextFeed, agg.fetchFailed(nextFeed.ID))
+		return
+	}
+	feed, err := s.Fetcher.Fetch(context.Background(), nextFeed.Url)
+	release()
+
+	if err != nil {
+		handleFetchError(s, agg, &nextFeed, err)
+		return
+	}
+	agg.fetchSucceeded(nextFeed.ID)
+
+	if feed.PermanentURL != "" {
+		if agg.replaying {
+			log.Printf("Recording of feed %s redirects to %s, keeping the URL while replaying\n", nextFeed.Url, feed.PermanentURL)
+		} else if movedFeed, err := moveFeed(s, nextFeed, feed.PermanentURL); err != nil {
+			log.Printf("Error updating URL of feed %s to %s: %v\n", nextFeed.Url, feed.PermanentURL, err)
+		} else {
+			nextFeed = movedFeed
+		}
+	}
+
+	processFeed(s, feed, &nextFeed, agg.minInterval, agg.maxInterval)
+}
+
+// processFeed stores everything learned from a successful fetch of dbFeed.
+func processFeed(s *State, feed *rss.Feed, dbFeed *database.Feed, minInterval, maxInterval time.Duration) {
+	saveRefreshHints(s, feed, dbFeed)
+	saveHubSubscription(s, feed, dbFeed)
+	savePostsToDB(s, feed, dbFeed)
+	scheduleNextFetch(s, dbFeed, minInterval, maxInterval)
+}
+
+// scheduleNextFetch sets when feed is fetched next, based on how often it
+// has published recently.
+func scheduleNextFetch(s *State, feed *database.Feed, minInterval, maxInterval time.Duration) {
+	published, err := s.DBQueries.GetRecentPostTimesForFeed(context.Background(), database.GetRecentPostTimesForFeedParams{
+		FeedID: feed.ID,
+		Limit:  postHistorySize,
+	})
+	if err != nil {
+		log.Printf("Error reading post history of feed %s: %v\n", feed.Url, err)
+		return
+	}
+
+	var times []time.Time
+	for _, t := range published {
+		if t.Valid {
+			times = append(times, t.Time)
+		}
+	}
+
+	interval := pollInterval(times, time.Now(), minInterval, maxInterval)
+	if hasActiveWebSubSubscription(s, feed) {
+		// New posts are pushed by the hub; poll only as a fallback.
+		interval = maxInterval
+	}
+	err = s.DBQueries.PostponeFeedFetch(context.Background(), database.PostponeFeedFetchParams{
+		DelaySeconds: int32(interval / time.Second),
+		ID:           feed.ID,
+	})
+	if err != nil {
+		log.Printf("Error scheduling next fetch of feed %s: %v\n", feed.Url, err)
+	}
+}
+
+// pollInterval estimates how often a feed publishes as the time spanned by
+// its recent posts, up to now, divided by their number, clamped to
+// [minInterval, maxInterval]. Measuring up to now rather than to the newest
+// post makes feeds that have gone quiet back off gradually.
+func pollInterval(published []time.Time, now time.Time, minInterval, maxInterval time.Duration) time.Duration {
+	oldest := now
+	count := 0
+	for _, t := range published {
+		if t.After(now) {
+			continue
+		}
+		if t.Before(oldest) {
+			oldest = t
+		}
+		count++
+	}
+	if count == 0 {
+		return maxInterval
+	}
+
+	interval := now.Sub(oldest) / time.Duration(count)
+	return min(max(interval, minInterval), maxInterval)
+}
+
+// saveRefreshHints stores the publisher's ttl, skipHours and skipDays so
+// that feed selection can respect them.
+func saveRefreshHints(s *State, feed *rss.Feed, dbFeed *database.Feed) {
+	interval, ok := feed.RefreshInterval()
+	err := s.DBQueries.UpdateFeedRefreshHints(context.Background(), database.UpdateFeedRefreshHintsParams{
+		ID:                dbFeed.ID,
+		MinRefreshSeconds: sql.NullInt32{Int32: int32(interval / time.Second), Valid: ok},
+		SkipHours:         feed.SkipHoursMask(),
+		SkipDays:          feed.SkipDaysMask(),
+	})
+	if err != nil {
+		log.Printf("Error saving refresh hints for feed %s: %v\n", dbFeed.Url, err)
+	}
+}
+
+// handleFetchError deals with a failed fetch of feed. Whatever went wrong,
+// the feed's next fetch is pushed back, further with every consecutive
+// failure, so broken feeds don't crowd out the working ones.
+func handleFetchError(s *State, agg *aggregator, feed *database.Feed, err error) {
+	delay := agg.fetchFailed(feed.ID)
+
+	var statusErr *rss.StatusError
+	if !errors.As(err, &statusErr) {
+		// One unreachable or broken feed should not stop the aggregator.
+		log.Printf("Error fetching feed %s, retrying in %v: %v\n", feed.Url, delay, err)
+		postponeFeed(s, feed, delay)
+		return
+	}
+
+	switch statusErr.StatusCode {
+	case http.StatusGone:
+		if agg.replaying {
+			log.Printf("Recording of feed %s is gone (410), not disabling it while replaying\n", feed.Url)
+			postponeFeed(s, feed, delay)
+		} else if err := disableFeed(s, feed, "feed is gone (410)"); err != nil {
+			log.Println(err)
+			postponeFeed(s, feed, delay)
+		}
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		retryAfter := statusErr.RetryAfter
+		if retryAfter <= 0 {
+			retryAfter = defaultRetryAfter
+		}
+		retryAfter = min(retryAfter, maxRetryAfter)
+
+		agg.limiter.Backoff(hostlimit.Host(feed.Url), time.Now().Add(retryAfter))
+		delay = max(delay, retryAfter)
+		log.Printf("Feed %s answered %s, retrying in %v\n", feed.Url, statusErr.Status, delay)
+		postponeFeed(s, feed, delay)
+	default:
+		log.Printf("Error fetching feed %s, retrying in %v: %v\n", feed.Url, delay, err)
+		postponeFeed(s, feed, delay)
+	}
+}
+
+// postponeFeed moves the next fetch of feed delay into the future.
+func postponeFeed(s *State, feed *database.Feed, delay time.Duration) {
+	err := s.DBQueries.PostponeFeedFetch(context.Background(), database.PostponeFeedFetchParams{
+		DelaySeconds: int32((max(delay, time.Second) + time.Second - 1) / time.Second),
+		ID:           feed.ID,
+	})
+	if err != nil {
+		log.Printf("Error postponing feed %s: %v\n", feed.Url, err)
+	}
+}
+
+// savePostsToDB stores the items of feed as posts of dbFeed, skipping posts
+// that were saved before.
+func savePostsToDB(s *State, feed *rss.Feed, dbFeed *database.Feed) {
+	for _, item := range feed.Channel.Items {
+		post, err := savePostToDB(s, &item, dbFeed)
+		if err != nil {
+			if isDuplicateURLError(err) {
+				continue
+			}
+			log.Printf("Error saving post with URL %s: %v\n", item.Link, err)
+			continue
+		}
+
+		err = saveEnclosuresToDB(s, feed, &item, post.ID)
+		if err != nil {
+			log.Printf("Error saving enclosures for post with URL %s: %v\n", item.Link, err)
+		}
+
+		err = saveCategoriesToDB(s, &item, post.ID)
+		if err != nil {
+			log.Printf("Error saving categories for post with URL %s: %v\n", item.Link, err)
+		}
+	}
+}
+
+func disableFeed(s *State, feed *database.Feed, reason string) error {
+	err := s.DBQueries.DisableFeed(context.Background(), database.DisableFeedParams{
+		ID:             feed.ID,
+		DisabledReason: sql.NullString{String: reason, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to disable feed %s: %w", feed.Url, err)
+	}
+	log.Printf("Disabled feed %s: %s\n", feed.Url, reason)
+	return nil
+}
+
+// moveFeed points feed at newURL after a permanent redirect. If another
+// feed already uses newURL, follows and posts are merged into it and the
+// old feed is deleted; the surviving feed is returned.
+func moveFeed(s *State, feed database.Feed, newURL string) (database.Feed, error) {
+	ctx := context.Background()
+	var moved database.Feed
+	err := s.DBQueries.WithTx(ctx, func(q database.Querier) error {
+		existing, err := q.GetFeedFromUrl(ctx, newURL)
+		if err == sql.ErrNoRows {
+			moved, err = q.UpdateFeedUrl(ctx, database.UpdateFeedUrlParams{
+				ID:  feed.ID,
+				Url: newURL,
+			})
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		err = q.MoveFeedFollows(ctx, database.MoveFeedFollowsParams{
+			TargetFeedID: existing.ID,
+			SourceFeedID: feed.ID,
+		})
+		if err != nil {
+			return err
+		}
+		err = q.MoveFeedPosts(ctx, database.MoveFeedPostsParams{
+			TargetFeedID: existing.ID,
+			SourceFeedID: feed.ID,
+		})
+		if err != nil {
+			return err
+		}
+		moved = existing
+		return q.DeleteFeed(ctx, feed.ID)
+	})
+	if err != nil {
+		return feed, err
+	}
+
+	if moved.ID != feed.ID {
+		log.Printf("Feed %s moved permanently to %s, merged into existing feed %q\n", feed.Url, newURL, moved.Name)
+	} else {
+		log.Printf("Feed %s moved permanently to %s\n", feed.Url, newURL)
+	}
+	return moved, nil
+}
+
+func savePostToDB(s *State, item *rss.Item, feed *database.Feed) (database.CreatePostRow, error) {
+	publishedAt, err := item.PublishedTime()
+	if err != nil {
+		return database.CreatePostRow{}, err
+	}
+	author := item.AuthorName()
+	return s.DBQueries.CreatePost(context.Background(), database.CreatePostParams{
+		Title:       item.Title,
+		Url:         item.Link,
+		Description: sql.NullString{String: markup.Sanitize(item.Description), Valid: true},
+		PublishedAt: sql.NullTime{Time: publishedAt, Valid: true},
+		FeedID:      feed.ID,
+		Author:      sql.NullString{String: author, Valid: author != ""},
+	})
+}
+
+func saveCategoriesToDB(s *State, item *rss.Item, postID int32) error {
+	for _, name := range item.CategoryNames() {
+		category, err := s.DBQueries.UpsertCategory(context.Background(), name)
+		if err != nil {
+			return err
+		}
+		err = s.DBQueries.CreatePostCategory(context.Background(), database.CreatePostCategoryParams{
+			PostID:     postID,
+			CategoryID: category.ID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func saveEnclosuresToDB(s *State, feed *rss.Feed, item *rss.Item, postID int32) error {
+	duration, hasDuration := item.DurationSeconds()
+	episode, hasEpisode := item.EpisodeNumber()
+	season, hasSeason := item.SeasonNumber()
+	imageURL := item.ImageURL(feed)
+
+	for _, enclosure := range item.Enclosures {
+		if enclosure.URL == "" {
+			continue
+		}
+		length, hasLength := enclosure.Size()
+		err := s.DBQueries.CreatePostEnclosure(context.Background(), database.CreatePostEnclosureParams{
+			PostID:          postID,
+			Url:             enclosure.URL,
+			Length:          sql.NullInt64{Int64: length, Valid: hasLength},
+			MimeType:        sql.NullString{String: enclosure.Type, Valid: enclosure.Type != ""},
+			DurationSeconds: sql.NullInt32{Int32: int32(duration), Valid: hasDuration},
+			Episode:         sql.NullInt32{Int32: int32(episode), Valid: hasEpisode},
+			Season:          sql.NullInt32{Int32: int32(season), Valid: hasSeason},
+			ImageUrl:        sql.NullString{String: imageURL, Valid: imageURL != ""},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isDuplicateURLError reports whether saving a post failed because its
+// URL is already stored, in the wording of either Postgres or SQLite.
+func isDuplicateURLError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return msg == "pq: duplicate key value violates unique constraint \"posts_url_key\"" ||
+		strings.Contains(msg, "UNIQUE constraint failed: posts.url")
+}
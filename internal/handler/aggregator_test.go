@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Shubham-Hazra/blog-aggregator/pkg/hostlimit"
+	"github.com/Shubham-Hazra/blog-aggregator/pkg/rss"
+)
+
+// fakeFetcher fails for the URLs in errs and serves testFeed for all
+// others, counting the fetches of each URL.
+type fakeFetcher struct {
+	errs map[string]error
+
+	mu      sync.Mutex
+	fetches map[string]int
+}
+
+func (f *fakeFetcher) Fetch(ctx context.Context, feedURL string) (*rss.Feed, error) {
+	f.mu.Lock()
+	if f.fetches == nil {
+		f.fetches = make(map[string]int)
+	}
+	f.fetches[feedURL]++
+	f.mu.Unlock()
+
+	if err := f.errs[feedURL]; err != nil {
+		return nil, err
+	}
+	return rss.Parse([]byte(testFeed), "application/rss+xml")
+}
+
+func TestPollInterval(t *testing.T) {
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) time.Time { return now.Add(-d) }
+	const minInterval, maxInterval = 10 * time.Minute, 24 * time.Hour
+
+	tests := []struct {
+		name      string
+		published []time.Time
+		want      time.Duration
+	}{
+		{"no posts", nil, maxInterval},
+		{"only future posts", []time.Time{now.Add(time.Hour)}, maxInterval},
+		{"hourly", []time.Time{ago(time.Hour), ago(2 * time.Hour), ago(3 * time.Hour)}, time.Hour},
+		{"order does not matter", []time.Time{ago(3 * time.Hour), ago(time.Hour), ago(2 * time.Hour)}, time.Hour},
+		{"future posts ignored", []time.Time{ago(2 * time.Hour), ago(4 * time.Hour), now.Add(time.Hour)}, 2 * time.Hour},
+		{"clamped to the minimum", []time.Time{ago(time.Minute), ago(2 * time.Minute)}, minInterval},
+		{"clamped to the maximum", []time.Time{ago(30 * 24 * time.Hour)}, maxInterval},
+		{"gone quiet", []time.Time{ago(10 * time.Hour), ago(11 * time.Hour)}, 11 * time.Hour / 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pollInterval(tt.published, now, minInterval, maxInterval); got != tt.want {
+				t.Errorf("pollInterval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFailureBackoff(t *testing.T) {
+	const minInterval, maxInterval = 10 * time.Minute, time.Hour
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{1, 10 * time.Minute},
+		{2, 20 * time.Minute},
+		{3, 40 * time.Minute},
+		{4, time.Hour},
+		{100, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := failureBackoff(tt.failures, minInterval, maxInterval); got != tt.want {
+			t.Errorf("failureBackoff(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestFetchErrorsPostponeFeed(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"network error", errors.New("connection refused")},
+		{"not found", &rss.StatusError{StatusCode: 404, Status: "404 Not Found"}},
+		{"server error", &rss.StatusError{StatusCode: 500, Status: "500 Internal Server Error"}},
+		{"not a feed", &rss.NotAFeedError{Reason: "got HTML"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestState(t)
+			ctx := context.Background()
+			alice := mustCreateUser(t, s, "alice")
+			feed := mustCreateFeed(t, s, alice, "https://example.com/feed")
+			s.Fetcher = &fakeFetcher{errs: map[string]error{feed.Url: tt.err}}
+			agg := &aggregator{
+				limiter:     hostlimit.New(1, 0),
+				batchSize:   1,
+				minInterval: 10 * time.Minute,
+				maxInterval: 30 * time.Minute,
+			}
+
+			failAndCheck := func(want time.Duration) {
+				t.Helper()
+				before := time.Now()
+				scrapeFeed(s, agg, feed)
+				got, err := s.DBQueries.GetFeed(ctx, feed.ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				delay := got.NextFetchAt.Time.Sub(before)
+				if !got.NextFetchAt.Valid || delay < want || delay > want+time.Minute {
+					t.Fatalf("next fetch in %v, want %v", delay, want)
+				}
+			}
+			for _, want := range []time.Duration{10 * time.Minute, 20 * time.Minute, 30 * time.Minute, 30 * time.Minute} {
+				failAndCheck(want)
+			}
+
+			// A successful fetch resets the backoff.
+			s.Fetcher = &fakeFetcher{}
+			scrapeFeed(s, agg, feed)
+			s.Fetcher = &fakeFetcher{errs: map[string]error{feed.Url: tt.err}}
+			failAndCheck(10 * time.Minute)
+		})
+	}
+}
+
+func TestBrokenFeedsDoNotStarveOthers(t *testing.T) {
+	s := newTestState(t)
+	alice := mustCreateUser(t, s, "alice")
+	fetcher := &fakeFetcher{errs: map[string]error{}}
+	for _, url := range []string{"https://a.example/feed", "https://b.example/feed", "https://c.example/feed"} {
+		mustCreateFeed(t, s, alice, url)
+		fetcher.errs[url] = errors.New("connection refused")
+	}
+	healthy := mustCreateFeed(t, s, alice, "https://d.example/feed")
+	s.Fetcher = fetcher
+	agg := &aggregator{
+		limiter:     hostlimit.New(1, 0),
+		batchSize:   2,
+		minInterval: defaultMinInterval,
+		maxInterval: defaultMaxInterval,
+	}
+
+	for range 3 {
+		if err := scrapeFeeds(s, agg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for url, n := range fetcher.fetches {
+		if n != 1 {
+			t.Errorf("%s fetched %d times, want once", url, n)
+		}
+	}
+	if fetcher.fetches[healthy.Url] != 1 {
+		t.Errorf("healthy feed fetched %d times, want once", fetcher.fetches[healthy.Url])
+	}
+}
+
+func TestReplayKeepsFeeds(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/feed", http.StatusMovedPermanently)
+		case "/feed":
+			w.Header().Set("Content-Type", "application/rss+xml")
+			io.WriteString(w, testFeed)
+		case "/gone":
+			http.Error(w, "gone", http.StatusGone)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	recorder := rss.NewRecorder(rss.NewFetcher(rss.FetcherOptions{}), dir)
+	for _, path := range []string{"/old", "/gone"} {
+		recorder.Fetch(context.Background(), server.URL+path)
+	}
+	server.Close()
+
+	s := newTestState(t)
+	ctx := context.Background()
+	alice := mustCreateUser(t, s, "alice")
+	moved := mustCreateFeed(t, s, alice, server.URL+"/old")
+	gone := mustCreateFeed(t, s, alice, server.URL+"/gone")
+	s.Fetcher = rss.NewReplayer(dir)
+	agg := &aggregator{
+		limiter:     hostlimit.New(1, 0),
+		batchSize:   1,
+		minInterval: defaultMinInterval,
+		maxInterval: defaultMaxInterval,
+		replaying:   true,
+	}
+
+	// Replaying again must find the same recordings.
+	for range 2 {
+		scrapeFeed(s, agg, moved)
+		scrapeFeed(s, agg, gone)
+	}
+
+	got, err := s.DBQueries.GetFeed(ctx, moved.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Url != moved.Url {
+		t.Errorf("replaying a redirect moved the feed to %s", got.Url)
+	}
+	if _, ok := agg.failures[moved.ID]; ok {
+		t.Errorf("replaying the redirected feed failed")
+	}
+	got, err = s.DBQueries.GetFeed(ctx, gone.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.DisabledAt.Valid {
+		t.Errorf("replaying a 410 disabled the feed")
+	}
+	posts, err := s.DBQueries.ListAllPosts(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(posts) != 1 {
+		t.Errorf("got %d posts from the replayed feed, want 1", len(posts))
+	}
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/Shubham-Hazra/blog-aggregator/internal/database"
+	"github.com/Shubham-Hazra/blog-aggregator/pkg/types"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// sessionLifetime is how long a login stays valid, in seconds.
+const sessionLifetime = 30 * 24 * 60 * 60
+
+var errInvalidLogin = errors.New("invalid user name or password")
+
+// HandlePasswd sets, changes or removes the current user's password
+func HandlePasswd(s *State, cmd types.Command, user database.User) error {
+	if err := validateArgCount(cmd.Args, 0, "passwd"); err != nil {
+		return err
+	}
+
+	if user.PasswordHash.Valid {
+		current, err := readPassword("Current password: ")
+		if err != nil {
+			return err
+		}
+		if err := checkPassword(user, current); err != nil {
+			return err
+		}
+	}
+
+	password, err := promptNewPassword()
+	if err != nil {
+		return err
+	}
+	hash, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	err = s.DBQueries.SetUserPassword(ctx, database.SetUserPasswordParams{
+		ID:           user.ID,
+		PasswordHash: hash,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to change password: %w", err)
+	}
+
+	// Log out everywhere else, the old password may have been the
+	// reason for the change.
+	err = s.DBQueries.DeleteOtherSessionsForUser(ctx, database.DeleteOtherSessionsForUserParams{
+		UserID:    user.ID,
+		TokenHash: hashSessionToken(s.Config.SESSION_TOKEN),
+	})
+	if err != nil {
+		return err
+	}
+
+	if hash.Valid {
+		fmt.Println("Password changed")
+	} else {
+		fmt.Println("Password removed")
+	}
+	return nil
+}
+
+// hashPassword returns the bcrypt hash of password, or NULL for an
+// empty password.
+func hashPassword(password string) (sql.NullString, error) {
+	if password == "" {
+		return sql.NullString{}, nil
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("could not hash password: %w", err)
+	}
+	return sql.NullString{String: string(hash), Valid: true}, nil
+}
+
+func checkPassword(user database.User, password string) error {
+	if !user.PasswordHash.Valid {
+		return nil
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash.String), []byte(password)); err != nil {
+		return errInvalidLogin
+	}
+	return nil
+}
+
+// startSession creates a new session for the user and stores its token
+// in the config file, ending the session it replaces.
+func startSession(s *State, user database.User) error {
+	ctx := context.Background()
+
+	if s.Config.SESSION_TOKEN != "" {
+		if err := s.DBQueries.DeleteSession(ctx, hashSessionToken(s.Config.SESSION_TOKEN)); err != nil {
+			log.Printf("Could not end the previous session: %v\n", err)
+		}
+	}
+	if err := s.DBQueries.DeleteExpiredSessions(ctx); err != nil {
+		log.Printf("Could not clean up expired sessions: %v\n", err)
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+
+	err := s.DBQueries.CreateSession(ctx, database.CreateSessionParams{
+		TokenHash:       hashSessionToken(token),
+		UserID:          user.ID,
+		LifetimeSeconds: sessionLifetime,
+	})
+	if err != nil {
+		return fmt.Errorf("could not create session: %w", err)
+	}
+
+	return s.Config.SetSession(token)
+}
+
+func hashSessionToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
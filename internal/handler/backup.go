@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Shubham-Hazra/blog-aggregator/internal/backup"
+	"github.com/Shubham-Hazra/blog-aggregator/internal/database"
+	"github.com/Shubham-Hazra/blog-aggregator/pkg/types"
+)
+
+// HandleBackup writes the whole database to a compressed JSON archive
+func HandleBackup(s *State, cmd types.Command, user database.User) error {
+	if err := validateArgCount(cmd.Args, 1, "backup"); err != nil {
+		return err
+	}
+
+	path := cmd.Args[0]
+	archive, err := dumpDatabase(s)
+	if err != nil {
+		return fmt.Errorf("unable to read database: %w", err)
+	}
+	if err := backup.WriteFile(path, archive); err != nil {
+		return fmt.Errorf("unable to write backup: %w", err)
+	}
+
+	fmt.Printf("Backed up %d user(s), %d feed(s), %d follow(s) and %d post(s) to %s\n",
+		len(archive.Users), len(archive.Feeds), len(archive.FeedFollows), len(archive.Posts), path)
+	return nil
+}
+
+// HandleRestore loads a backup into an empty database. It doesn't need a
+// login, since there are no users to log in as before the restore.
+func HandleRestore(s *State, cmd types.Command) error {
+	if err := validateArgCount(cmd.Args, 1, "restore"); err != nil {
+		return err
+	}
+
+	path := cmd.Args[0]
+	archive, err := backup.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to read backup: %w", err)
+	}
+
+	ctx := context.Background()
+	err = s.DBQueries.WithTx(ctx, func(q database.Querier) error {
+		return backup.Restore(ctx, q, archive)
+	})
+	if errors.Is(err, backup.ErrNotEmpty) {
+		return errors.New("restore only works on an empty database, run reset --all first")
+	} else if err != nil {
+		return fmt.Errorf("unable to restore backup: %w", err)
+	}
+
+	fmt.Printf("Restored %d user(s), %d feed(s), %d follow(s) and %d post(s) from %s (taken %s)\n",
+		len(archive.Users), len(archive.Feeds), len(archive.FeedFollows), len(archive.Posts),
+		path, archive.CreatedAt.Local().Format("2006-01-02 15:04"))
+	fmt.Println("Sessions are not part of backups, use login to log in again")
+	return nil
+}
+
+func writeBackup(s *State, path string) error {
+	archive, err := dumpDatabase(s)
+	if err != nil {
+		return err
+	}
+	return backup.WriteFile(path, archive)
+}
+
+// dumpDatabase reads a consistent snapshot of every table.
+func dumpDatabase(s *State) (*backup.Archive, error) {
+	var archive *backup.Archive
+	err := s.DBQueries.WithSnapshot(context.Background(), func(q database.Querier) error {
+		var err error
+		archive, err = backup.Dump(context.Background(), q)
+		return err
+	})
+	return archive, err
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/Shubham-Hazra/blog-aggregator/internal/database"
+	"github.com/Shubham-Hazra/blog-aggregator/pkg/rss"
+	"github.com/Shubham-Hazra/blog-aggregator/pkg/types"
+)
+
+var errAborted = errors.New("aborted")
+
+// HandleRemoveFeed deletes a feed together with its follows and posts
+func HandleRemoveFeed(s *State, cmd types.Command, user database.User) error {
+	flags := flag.NewFlagSet("removefeed", flag.ContinueOnError)
+	yes := flags.Bool("yes", false, "do not ask for confirmation")
+	if err := flags.Parse(cmd.Args); err != nil {
+		return err
+	}
+	if err := validateArgCount(flags.Args(), 1, "removefeed"); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	feed, err := getOwnedFeed(s, flags.Arg(0), user)
+	if err != nil {
+		return err
+	}
+
+	if !*yes {
+		stats, err := s.DBQueries.GetFeedStats(ctx, feed.ID)
+		if err != nil {
+			return err
+		}
+		ok, err := confirm(fmt.Sprintf("Remove feed %q with %d post(s), followed by %d user(s)?",
+			feed.Name, stats.PostCount, stats.FollowerCount))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errAborted
+		}
+	}
+
+	// Follows, posts and everything hanging off posts are removed by
+	// the ON DELETE CASCADE constraints.
+	if err := s.DBQueries.DeleteFeed(ctx, feed.ID); err != nil {
+		return fmt.Errorf("unable to remove feed: %w", err)
+	}
+
+	fmt.Printf("Removed feed %s (%s)\n", feed.Name, feed.Url)
+	return nil
+}
+
+// HandleRenameFeed changes the display name of a feed
+func HandleRenameFeed(s *State, cmd types.Command, user database.User) error {
+	if err := validateArgCount(cmd.Args, 2, "renamefeed"); err != nil {
+		return err
+	}
+
+	feed, err := getOwnedFeed(s, cmd.Args[0], user)
+	if err != nil {
+		return err
+	}
+
+	renamed, err := s.DBQueries.RenameFeed(context.Background(), database.RenameFeedParams{
+		ID:   feed.ID,
+		Name: cmd.Args[1],
+	})
+	if err != nil {
+		return fmt.Errorf("unable to rename feed: %w", err)
+	}
+
+	fmt.Printf("Renamed feed %s to %s\n", feed.Name, renamed.Name)
+	return nil
+}
+
+// HandleSetFeedURL points an existing feed at a new URL, keeping its
+// follows and posts
+func HandleSetFeedURL(s *State, cmd types.Command, user database.User) error {
+	flags := flag.NewFlagSet("setfeedurl", flag.ContinueOnError)
+	noFetch := flags.Bool("no-fetch", false, "change the URL without checking the new feed first")
+	yes := flags.Bool("yes", false, "do not ask for confirmation")
+	if err := flags.Parse(cmd.Args); err != nil {
+		return err
+	}
+	if err := validateArgCount(flags.Args(), 2, "setfeedurl"); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	newURL := flags.Arg(1)
+	feed, err := getOwnedFeed(s, flags.Arg(0), user)
+	if err != nil {
+		return err
+	}
+
+	if err := rss.ValidateURL(newURL); err != nil {
+		return fmt.Errorf("invalid feed URL %s: %w", newURL, err)
+	}
+	existing, err := s.DBQueries.GetFeedFromUrl(ctx, newURL)
+	if err == nil {
+		return fmt.Errorf("feed %s already uses %s", existing.Name, newURL)
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	if !*noFetch {
+		report := rss.CheckWith(ctx, s.Fetcher, newURL)
+		if err := report.Err(); err != nil {
+			return fmt.Errorf("%w\nuse --no-fetch to change it anyway", err)
+		}
+		printWarnings(report.Warnings)
+	}
+
+	if !*yes {
+		ok, err := confirm(fmt.Sprintf("Change the URL of %q from %s to %s?", feed.Name, feed.Url, newURL))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errAborted
+		}
+	}
+
+	updated, err := s.DBQueries.UpdateFeedUrl(ctx, database.UpdateFeedUrlParams{
+		ID:  feed.ID,
+		Url: newURL,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to change feed URL: %w", err)
+	}
+
+	fmt.Printf("Feed %s now fetches from %s\n", updated.Name, updated.Url)
+	return nil
+}
+
+// getOwnedFeed looks a feed up by URL and makes sure the user is
+// allowed to change it, which admins always are.
+func getOwnedFeed(s *State, feedURL string, user database.User) (database.Feed, error) {
+	feed, err := s.DBQueries.GetFeedFromUrl(context.Background(), feedURL)
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.Feed{}, fmt.Errorf("no feed with URL %s", feedURL)
+	} else if err != nil {
+		return database.Feed{}, err
+	}
+	if feed.UserID != user.ID && !isAdmin(user) {
+		return database.Feed{}, fmt.Errorf("feed %s was added by another user, only its creator or an admin can change it", feed.Name)
+	}
+	return feed, nil
+}
@@ -3,14 +3,21 @@ package handler
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
+	"net/url"
 	"os"
+	"path"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/Shubham-Hazra/blog-aggregator/internal/database"
+	"github.com/Shubham-Hazra/blog-aggregator/pkg/download"
+	"github.com/Shubham-Hazra/blog-aggregator/pkg/markup"
 	"github.com/Shubham-Hazra/blog-aggregator/pkg/rss"
 	"github.com/Shubham-Hazra/blog-aggregator/pkg/types"
 	"github.com/google/uuid"
@@ -27,15 +34,32 @@ func NewHandler(state *State) (h *Handler){
 	h.commandMap = map[string]func(*State, types.Command) error{
 			"login":     HandleLogin,
 			"register":  HandleRegister,
-			"reset":     HandleReset,
+			"reset":     middlewareAdmin(HandleReset),
+			"backup":    middlewareAdmin(HandleBackup),
+			"restore":   HandleRestore,
+			"migrate":   HandleMigrate,
 			"users":     HandleUsers,
 			"agg":       HandleAgg,
+			"serve":     HandleServe,
 			"addfeed":   middlewareLoggedIn(HandleAddFeed),
+			"removefeed": middlewareLoggedIn(HandleRemoveFeed),
+			"renamefeed": middlewareLoggedIn(HandleRenameFeed),
+			"setfeedurl": middlewareLoggedIn(HandleSetFeedURL),
 			"feeds":     HandleFeeds,
+			"checkfeed": HandleCheckFeed,
 			"follow":    middlewareLoggedIn(HandleFollow),
 			"unfollow":    middlewareLoggedIn(HandleUnfollow),
 			"following": middlewareLoggedIn(HandleFollowing),
 			"browse": middlewareLoggedIn(HandleBrowse),
+			"episodes":  middlewareLoggedIn(HandleEpisodes),
+			"download":  middlewareLoggedIn(HandleDownload),
+			"passwd":    middlewareLoggedIn(HandlePasswd),
+			"setrole":   middlewareAdmin(HandleSetRole),
+			"deleteuser": middlewareAdmin(HandleDeleteUser),
+			"renameuser": middlewareLoggedIn(HandleRenameUser),
+			"deactivate": middlewareLoggedIn(HandleDeactivate),
+			"reactivate": middlewareAdmin(HandleReactivate),
+			"help":      HandleHelp,
 		}
 	return h
 }
@@ -48,95 +72,126 @@ func (h *Handler) Execute(cmd types.Command) error {
 	return handler(h.state, cmd)
 }
 
-// HandleAgg handles the aggregation of RSS feeds
-func HandleAgg(s *State, cmd types.Command) error {
-
-	time_between_reqs := cmd.Args[0]
-	timeBetweenRequests, err := time.ParseDuration(time_between_reqs)
-	if err != nil {
+func HandleBrowse(s *State, cmd types.Command, user database.User) error {
+	flags := flag.NewFlagSet("browse", flag.ContinueOnError)
+	author := flags.String("author", "", "only show posts by this author")
+	category := flags.String("category", "", "only show posts in this category")
+	if err := flags.Parse(cmd.Args); err != nil {
 		return err
 	}
 
-	fmt.Println("Collecting feeds every " + timeBetweenRequests.String())
-	ticker := time.NewTicker(timeBetweenRequests)
-	for ; ; <-ticker.C {
-		err := scrapeFeeds(s)
+	var limit int = 2
+	if flags.NArg() != 0 {
+		var err error
+		limit, err = strconv.Atoi(flags.Arg(0))
 		if err != nil {
-			return err
+			return err 
 		}
 	}
-}
 
-func scrapeFeeds(s *State) error {
-	nextFeed, err := s.DBQueries.GetNextFeedToFetch(context.Background())
+	categoryName := rss.NormalizeCategory(*category)
+	posts , err:= s.DBQueries.GetPostsForUser(context.Background(), database.GetPostsForUserParams{
+		UserID:   user.ID,
+		Author:   sql.NullString{String: *author, Valid: *author != ""},
+		Category: sql.NullString{String: categoryName, Valid: categoryName != ""},
+		Limit:    int32(limit),
+	} )
 	if err != nil {
-		return err
+		return err 
 	}
 
-	err = s.DBQueries.MarkFeedFetched(context.Background(), nextFeed.ID)
-	if err != nil {
-		return err
+	for _, post := range posts {
+		printPostInfo(&post)
 	}
 
-	feed, err := rss.FetchFeed(context.Background(), nextFeed.Url)
-	if err != nil {
-		return err
-	}
+	return nil
+}
 
-	for _, item := range feed.Channel.Items{
-		err = savePostToDB(s, &item, &nextFeed)
+// HandleEpisodes lists recent podcast episodes from the feeds the user follows
+func HandleEpisodes(s *State, cmd types.Command, user database.User) error {
+	var limit int = 10
+	if len(cmd.Args) != 0 {
+		var err error
+		limit, err = strconv.Atoi(cmd.Args[0])
 		if err != nil {
-			if isDuplicateURLError(err) {
-				continue
-			}
-			log.Printf("Error saving post with URL %s: %v\n", item.Link, err)
+			return err
 		}
 	}
 
+	episodes, err := s.DBQueries.GetEpisodesForUser(context.Background(), database.GetEpisodesForUserParams{
+		UserID: user.ID,
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		return fmt.Errorf("error retrieving episodes: %w", err)
+	}
+
+	for _, episode := range episodes {
+		printEpisodeInfo(&episode)
+	}
+
 	return nil
 }
 
-func savePostToDB(s *State, item *rss.Item, feed *database.Feed) error {
-	publishedAt, err := time.Parse(time.RFC1123, item.PubDate)
-	if err != nil {
+// HandleDownload downloads enclosures for a post, or all new episodes of followed feeds
+func HandleDownload(s *State, cmd types.Command, user database.User) error {
+	flags := flag.NewFlagSet("download", flag.ContinueOnError)
+	dir := flags.String("dir", "", "directory to store downloads in")
+	newOnly := flags.Bool("new", false, "download all new episodes of followed feeds")
+	limit := flags.Int("limit", 20, "maximum number of episodes to download with --new")
+	if err := flags.Parse(cmd.Args); err != nil {
 		return err
 	}
-	err = s.DBQueries.CreatePost(context.Background(), database.CreatePostParams{
-		Title:       item.Title,
-		Url:         item.Link,
-		Description: sql.NullString{String: item.Description, Valid: true},
-		PublishedAt: sql.NullTime{Time: publishedAt, Valid: true},
-		FeedID:      feed.ID,
-	})
-	return err
-}
 
-func isDuplicateURLError(err error) bool {
-	return err != nil && err.Error() == "pq: duplicate key value violates unique constraint \"posts_url_key\""
-}
+	if *newOnly == (flags.NArg() == 1) || flags.NArg() > 1 {
+		return fmt.Errorf("usage: download [--dir <path>] (<post_url> | --new [--limit <n>])")
+	}
 
-func HandleBrowse(s *State, cmd types.Command, user database.User) error {
-	var limit int = 2
-	if len(cmd.Args) != 0 {
+	if *dir == "" {
 		var err error
-		limit, err = strconv.Atoi(cmd.Args[0])
+		*dir, err = s.Config.DownloadDir()
 		if err != nil {
-			return err 
+			return err
 		}
 	}
 
-	posts , err:= s.DBQueries.GetPostsForUser(context.Background(), database.GetPostsForUserParams{
-		UserID: user.ID,
-		Limit: int32(limit),
-	} )
-	if err != nil {
-		return err 
+	var targets []downloadTarget
+	if *newOnly {
+		rows, err := s.DBQueries.GetNewEnclosuresForUser(context.Background(), database.GetNewEnclosuresForUserParams{
+			UserID: user.ID,
+			Limit:  int32(*limit),
+		})
+		if err != nil {
+			return fmt.Errorf("error retrieving new episodes: %w", err)
+		}
+		for _, row := range rows {
+			targets = append(targets, downloadTarget{row.ID, row.Url, row.Length, row.FeedName})
+		}
+	} else {
+		rows, err := s.DBQueries.GetEnclosuresForPostUrl(context.Background(), flags.Arg(0))
+		if err != nil {
+			return fmt.Errorf("error retrieving enclosures: %w", err)
+		}
+		if len(rows) == 0 {
+			return fmt.Errorf("post %s has no enclosures", flags.Arg(0))
+		}
+		for _, row := range rows {
+			targets = append(targets, downloadTarget{row.ID, row.Url, row.Length, row.FeedName})
+		}
 	}
 
-	for _, post := range posts {
-		printPostInfo(&post)
+	downloader := download.New()
+	failed := 0
+	for _, target := range targets {
+		if err := downloadEnclosure(s, downloader, user, *dir, target); err != nil {
+			log.Printf("Error downloading %s: %v\n", target.URL, err)
+			failed++
+		}
 	}
 
+	if failed > 0 {
+		return fmt.Errorf("%d of %d download(s) failed", failed, len(targets))
+	}
 	return nil
 }
 
@@ -163,12 +218,26 @@ func HandleLogin(s *State, cmd types.Command) error {
 	}
 
 	userName := cmd.Args[0]
-	if _, err := s.DBQueries.GetUser(context.Background(), userName); err != nil {
+	user, err := s.DBQueries.GetUser(context.Background(), userName)
+	if err != nil {
 		fmt.Printf("Error: User %s is not registered\n", userName)
 		os.Exit(1)
 	}
+	if user.DeactivatedAt.Valid {
+		return fmt.Errorf("user %s has been deactivated", userName)
+	}
+
+	if user.PasswordHash.Valid {
+		password, err := readPassword("Password: ")
+		if err != nil {
+			return err
+		}
+		if err := checkPassword(user, password); err != nil {
+			return err
+		}
+	}
 
-	if err := s.Config.SetUser(userName); err != nil {
+	if err := startSession(s, user); err != nil {
 		return fmt.Errorf("error encountered while login: %w", err)
 	}
 
@@ -187,25 +256,60 @@ func HandleRegister(s *State, cmd types.Command) error {
 		return err
 	}
 
-	user, err := createUser(s, userName)
+	password, err := promptNewPassword()
+	if err != nil {
+		return err
+	}
+	passwordHash, err := hashPassword(password)
 	if err != nil {
 		return err
 	}
 
-	s.Config.SetUser(userName)
+	user, err := createUser(s, userName, passwordHash)
+	if err != nil {
+		return err
+	}
+
+	if err := startSession(s, user); err != nil {
+		return err
+	}
 	fmt.Printf("User %s has been successfully registered\n", userName)
+	if isAdmin(user) {
+		fmt.Printf("%s is the first user and has been made an admin\n", userName)
+	}
 	logUserDetails(user)
 	return nil
 }
 
 // HandleAddFeed adds a new feed to the system
 func HandleAddFeed(s *State, cmd types.Command, user database.User) error {
-	if err := validateArgCount(cmd.Args, 2, "addfeed"); err != nil {
+	flags := flag.NewFlagSet("addfeed", flag.ContinueOnError)
+	noFetch := flags.Bool("no-fetch", false, "add the feed without fetching it first")
+	if err := flags.Parse(cmd.Args); err != nil {
+		return err
+	}
+	if err := validateArgCount(flags.Args(), 2, "addfeed"); err != nil {
 		return err
 	}
 
-	feedName := cmd.Args[0]
-	feedURL := cmd.Args[1]
+	feedName := flags.Arg(0)
+	feedURL := flags.Arg(1)
+
+	if err := rss.ValidateURL(feedURL); err != nil {
+		return fmt.Errorf("invalid feed URL %s: %w", feedURL, err)
+	}
+
+	// Check before saving anything, so that a URL that isn't a feed
+	// never makes it into the database.
+	var fetched *rss.Feed
+	if !*noFetch {
+		report := rss.CheckWith(context.Background(), s.Fetcher, feedURL)
+		if err := report.Err(); err != nil {
+			return fmt.Errorf("%w\nuse --no-fetch to add it anyway", err)
+		}
+		printWarnings(report.Warnings)
+		fetched = report.Feed
+	}
 
 	feed, err := createFeed(s, feedName, feedURL, user.ID)
 	if err != nil {
@@ -217,10 +321,43 @@ func HandleAddFeed(s *State, cmd types.Command, user database.User) error {
 		return err
 	}
 
+	if fetched != nil {
+		if err := s.DBQueries.MarkFeedFetched(context.Background(), feed.ID); err != nil {
+			return err
+		}
+		processFeed(s, fetched, &feed, defaultMinInterval, defaultMaxInterval)
+		fmt.Printf("Fetched %d post(s) from %s\n", len(fetched.Channel.Items), feedURL)
+	} else if err := s.DBQueries.NotifyFeedAdded(context.Background(), strconv.Itoa(int(feed.ID))); err != nil {
+		log.Printf("Could not notify running aggregators of the new feed: %v\n", err)
+	}
+
 	logFeedDetails(feed)
 	return nil
 }
 
+// HandleCheckFeed runs the addfeed diagnostics on a URL without saving anything
+func HandleCheckFeed(s *State, cmd types.Command) error {
+	if err := validateArgCount(cmd.Args, 1, "checkfeed"); err != nil {
+		return err
+	}
+
+	report := rss.CheckWith(context.Background(), s.Fetcher, cmd.Args[0])
+	if report.Feed != nil {
+		fmt.Printf("Format: %s\nTitle: %s\nLink: %s\nItems: %d\n",
+			report.Feed.Format, report.Feed.Channel.Title, report.Feed.Channel.Link, len(report.Feed.Channel.Items))
+		if hubs := report.Feed.HubURLs(); len(hubs) > 0 {
+			fmt.Printf("WebSub hub: %s\n", hubs[0])
+		}
+	}
+	printWarnings(report.Warnings)
+
+	if err := report.Err(); err != nil {
+		return err
+	}
+	fmt.Println("Feed looks good")
+	return nil
+}
+
 // HandleFollow allows a user to follow a feed
 func HandleFollow(s *State, cmd types.Command, user database.User) error {
 	if err := validateArgCount(cmd.Args, 1, "follow"); err != nil {
@@ -286,13 +423,83 @@ func HandleFollowing(s *State, cmd types.Command, user database.User) error {
 }
 
 // HandleReset resets the database tables
-func HandleReset(s *State, cmd types.Command) error {
-	err := s.DBQueries.ResetTables(context.Background())
-	if err != nil {
+func HandleReset(s *State, cmd types.Command, user database.User) error {
+	flags := flag.NewFlagSet("reset", flag.ContinueOnError)
+	all := flags.Bool("all", false, "delete every user, feed and post")
+	posts := flags.Bool("posts", false, "delete all posts but keep users, feeds and follows")
+	userName := flags.String("user", "", "remove a user's follows and download records")
+	yes := flags.Bool("yes", false, "do not ask for confirmation")
+	backupPath := flags.String("backup", "", "write a backup to this file before resetting")
+	if err := flags.Parse(cmd.Args); err != nil {
+		return err
+	}
+	if err := validateArgCount(flags.Args(), 0, "reset"); err != nil {
+		return err
+	}
+
+	scopes := 0
+	for _, set := range []bool{*all, *posts, *userName != ""} {
+		if set {
+			scopes++
+		}
+	}
+	if scopes != 1 {
+		return errors.New("usage: reset [--yes] [--backup <file>] (--all | --posts | --user <name>)")
+	}
+
+	ctx := context.Background()
+	var question string
+	var reset func(q database.Querier) error
+	switch {
+	case *all:
+		question = "Delete ALL users, feeds, follows and posts?"
+		reset = func(q database.Querier) error {
+			return q.ResetTables(ctx)
+		}
+	case *posts:
+		question = "Delete all posts, episodes and download records?"
+		reset = func(q database.Querier) error {
+			if err := q.DeleteAllPosts(ctx); err != nil {
+				return err
+			}
+			return q.DeleteUnusedCategories(ctx)
+		}
+	default:
+		target, err := getUserByName(s, *userName)
+		if err != nil {
+			return err
+		}
+		question = fmt.Sprintf("Remove all follows and download records of %s?", target.Name)
+		reset = func(q database.Querier) error {
+			if err := q.DeleteAllFeedFollowsForUser(ctx, target.ID); err != nil {
+				return err
+			}
+			return q.DeleteDownloadsForUser(ctx, target.ID)
+		}
+	}
+
+	if !*yes {
+		ok, err := confirm(question)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errAborted
+		}
+	}
+
+	if *backupPath != "" {
+		if err := writeBackup(s, *backupPath); err != nil {
+			return fmt.Errorf("backup failed, nothing was reset: %w", err)
+		}
+		fmt.Printf("Wrote backup to %s\n", *backupPath)
+	}
+
+	if err := s.DBQueries.WithTx(ctx, reset); err != nil {
 		return fmt.Errorf("unable to reset tables: %v", err)
 	}
 
-	fmt.Println("Successfully reset all the tables")
+	fmt.Println("Reset complete")
 	return nil
 }
 
@@ -303,11 +510,19 @@ func HandleUsers(s *State, cmd types.Command) error {
 		return fmt.Errorf("unable to get users: %v", err)
 	}
 
+	current, _ := getCurrentUser(s)
 	for _, user := range users {
-		if user.Name == s.Config.CURRENT_USER_NAME {
-			fmt.Printf("* %s (current)\n", user.Name)
+		labels := ""
+		if isAdmin(user) {
+			labels = " [admin]"
+		}
+		if user.DeactivatedAt.Valid {
+			labels += " [deactivated]"
+		}
+		if user.ID == current.ID {
+			fmt.Printf("* %s%s (current)\n", user.Name, labels)
 		} else {
-			fmt.Printf("* %s\n", user.Name)
+			fmt.Printf("* %s%s\n", user.Name, labels)
 		}
 	}
 
@@ -324,11 +539,38 @@ func validateArgCount(args []string, expected int, commandName string) error {
 }
 
 func getCurrentUser(s *State) (database.User, error) {
-	user, err := s.DBQueries.GetUser(context.Background(), s.Config.CURRENT_USER_NAME)
-	if err != nil {
+	if s.Config.USER_NAME != "" {
+		return getEnvironmentUser(s)
+	}
+	if s.Config.SESSION_TOKEN == "" {
+		return database.User{}, errors.New("not logged in, use login <name> first")
+	}
+	user, err := s.DBQueries.GetUserForSession(context.Background(), hashSessionToken(s.Config.SESSION_TOKEN))
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.User{}, errors.New("session expired, use login <name> again")
+	} else if err != nil {
 		fmt.Println("Error: Could not retrieve user details from the database")
 		os.Exit(1)
 	}
+	if user.DeactivatedAt.Valid {
+		return database.User{}, fmt.Errorf("user %s has been deactivated", user.Name)
+	}
+	return user, nil
+}
+
+// getEnvironmentUser returns the user named by GATOR_USER. Skipping the
+// login is only allowed for accounts without a password.
+func getEnvironmentUser(s *State) (database.User, error) {
+	user, err := getUserByName(s, s.Config.USER_NAME)
+	if err != nil {
+		return database.User{}, err
+	}
+	if user.PasswordHash.Valid {
+		return database.User{}, fmt.Errorf("user %s has a password, use login instead of GATOR_USER", user.Name)
+	}
+	if user.DeactivatedAt.Valid {
+		return database.User{}, fmt.Errorf("user %s has been deactivated", user.Name)
+	}
 	return user, nil
 }
 
@@ -342,13 +584,14 @@ func validateNewUser(s *State, userName string) error {
 	return nil
 }
 
-func createUser(s *State, userName string) (database.User, error) {
+func createUser(s *State, userName string, passwordHash sql.NullString) (database.User, error) {
 	nullTime := getNullTime()
 	return s.DBQueries.CreateUser(context.Background(), database.CreateUserParams{
-		ID:        uuid.New(),
-		CreatedAt: nullTime,
-		UpdatedAt: nullTime,
-		Name:      userName,
+		ID:           uuid.New(),
+		CreatedAt:    nullTime,
+		UpdatedAt:    nullTime,
+		Name:         userName,
+		PasswordHash: passwordHash,
 	})
 }
 
@@ -382,6 +625,80 @@ func createFeedFollow(s *State, userID uuid.UUID, feedID int32) (database.Create
 	return followRow, nil
 }
 
+type downloadTarget struct {
+	EnclosureID int32
+	URL         string
+	Length      sql.NullInt64
+	FeedName    string
+}
+
+func downloadEnclosure(s *State, downloader *download.Downloader, user database.User, dir string, target downloadTarget) error {
+	existing, err := s.DBQueries.GetDownload(context.Background(), database.GetDownloadParams{
+		UserID:      user.ID,
+		EnclosureID: target.EnclosureID,
+	})
+	if err == nil {
+		ok, err := download.Verify(existing.Path, existing.Sha256)
+		if err != nil {
+			return err
+		}
+		if ok {
+			fmt.Printf("Already downloaded: %s\n", existing.Path)
+			return nil
+		}
+		log.Printf("Downloaded file %s is missing or corrupt, downloading again\n", existing.Path)
+	} else if err != sql.ErrNoRows {
+		return err
+	}
+
+	dest := filepath.Join(dir, sanitizeFileName(target.FeedName),
+		fmt.Sprintf("%d-%s", target.EnclosureID, enclosureFileName(target.URL)))
+
+	fmt.Printf("Downloading %s\n", target.URL)
+	result, err := downloader.Download(context.Background(), target.URL, dest)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.DBQueries.UpsertDownload(context.Background(), database.UpsertDownloadParams{
+		UserID:      user.ID,
+		EnclosureID: target.EnclosureID,
+		Path:        result.Path,
+		Size:        result.Size,
+		Sha256:      result.SHA256,
+	})
+	if err != nil {
+		return fmt.Errorf("error recording download: %w", err)
+	}
+
+	fmt.Printf("Saved %s (%d bytes, sha256 %s)\n", result.Path, result.Size, result.SHA256)
+	return nil
+}
+
+func enclosureFileName(rawURL string) string {
+	name := "download"
+	if parsed, err := url.Parse(rawURL); err == nil {
+		if base := path.Base(parsed.Path); base != "." && base != "/" {
+			name = base
+		}
+	}
+	return sanitizeFileName(name)
+}
+
+func sanitizeFileName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if name == "" || name == "." || name == ".." {
+		return "_"
+	}
+	return name
+}
+
 func getNullTime() sql.NullTime {
 	return sql.NullTime{
 		Time:  time.Now(),
@@ -393,11 +710,48 @@ func printDivider() {
 	fmt.Println(strings.Repeat("#", 50))
 }
 
+// descriptionWidth is the column at which post descriptions are wrapped.
+const descriptionWidth = 80
+
+func printWarnings(warnings []string) {
+	for _, warning := range warnings {
+		fmt.Printf("Warning: %s\n", warning)
+	}
+}
+
 func printPostInfo(post *database.GetPostsForUserRow) {
 	printDivider()
-	fmt.Printf("Feed Name: %v\nTitle: %v\nDescription: %v\nLink: %v\nPubDate: %v\n",
-		post.FeedName, post.Title, post.Description.String, post.Url, post.PublishedAt.Time)
+	fmt.Printf("Feed Name: %v\nTitle: %v\n", post.FeedName, post.Title)
+	if post.Author.Valid {
+		fmt.Printf("Author: %v\n", post.Author.String)
+	}
+	fmt.Printf("Description:\n%v\nLink: %v\nPubDate: %v\n",
+		markup.RenderText(post.Description.String, descriptionWidth), post.Url, post.PublishedAt.Time)
+	printDivider()
+}
+
+func printEpisodeInfo(episode *database.GetEpisodesForUserRow) {
 	printDivider()
+	fmt.Printf("Feed Name: %v\nTitle: %v\n", episode.FeedName, episode.PostTitle)
+	if episode.Season.Valid || episode.Episode.Valid {
+		fmt.Printf("Season: %v Episode: %v\n", formatNullInt(episode.Season), formatNullInt(episode.Episode))
+	}
+	fmt.Printf("Media URL: %v\n", episode.Url)
+	if episode.MimeType.Valid {
+		fmt.Printf("Type: %v\n", episode.MimeType.String)
+	}
+	if episode.DurationSeconds.Valid {
+		fmt.Printf("Duration: %v\n", time.Duration(episode.DurationSeconds.Int32)*time.Second)
+	}
+	fmt.Printf("PubDate: %v\n", episode.PublishedAt.Time)
+	printDivider()
+}
+
+func formatNullInt(value sql.NullInt32) string {
+	if !value.Valid {
+		return "-"
+	}
+	return strconv.Itoa(int(value.Int32))
 }
 
 func logUserDetails(user database.User) {
@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/Shubham-Hazra/blog-aggregator/internal/config"
+	"github.com/Shubham-Hazra/blog-aggregator/internal/database"
+	"github.com/Shubham-Hazra/blog-aggregator/internal/store"
+	"github.com/Shubham-Hazra/blog-aggregator/pkg/rss"
+	"github.com/Shubham-Hazra/blog-aggregator/pkg/types"
+)
+
+const testFeed = `<?xml version="1.0"?>
+<rss version="2.0">
+<channel>
+  <title>Test</title>
+  <link>https://example.com/</link>
+  <item>
+    <title>Episode 1</title>
+    <link>https://example.com/1</link>
+    <pubDate>Mon, 02 Jan 2006 15:04:05 GMT</pubDate>
+    <enclosure url="https://example.com/1.mp3" length="100" type="audio/mpeg"/>
+  </item>
+</channel>
+</rss>`
+
+func newTestState(t *testing.T) *State {
+	t.Helper()
+	s, err := NewState(&config.Config{}, store.NewMemory())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func mustCreateUser(t *testing.T, s *State, name string) database.User {
+	t.Helper()
+	user, err := createUser(s, name, sql.NullString{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+func mustCreateFeed(t *testing.T, s *State, owner database.User, url string) database.Feed {
+	t.Helper()
+	feed, err := createFeed(s, "Test", url, owner.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := createFeedFollow(s, owner.ID, feed.ID); err != nil {
+		t.Fatal(err)
+	}
+	return feed
+}
+
+func mustParseFeed(t *testing.T) *rss.Feed {
+	t.Helper()
+	feed, err := rss.Parse([]byte(testFeed), "application/rss+xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return feed
+}
+
+func TestDuplicatePostURLIsRecognised(t *testing.T) {
+	s := newTestState(t)
+	ctx := context.Background()
+	alice := mustCreateUser(t, s, "alice")
+	feed := mustCreateFeed(t, s, alice, "https://example.com/feed")
+	parsed := mustParseFeed(t)
+
+	item := &parsed.Channel.Items[0]
+	if _, err := savePostToDB(s, item, &feed); err != nil {
+		t.Fatalf("saving post: %v", err)
+	}
+	_, err := savePostToDB(s, item, &feed)
+	if !isDuplicateURLError(err) {
+		t.Fatalf("saving the post again: got %v, want a duplicate URL error", err)
+	}
+
+	// A second fetch of the same feed must not store anything twice.
+	savePostsToDB(s, parsed, &feed)
+	posts, err := s.DBQueries.ListAllPosts(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(posts) != 1 {
+		t.Errorf("got %d posts, want 1", len(posts))
+	}
+}
+
+func TestDeleteUserWithFeeds(t *testing.T) {
+	s := newTestState(t)
+	ctx := context.Background()
+	alice := mustCreateUser(t, s, "alice")
+	bob := mustCreateUser(t, s, "bob")
+	feed := mustCreateFeed(t, s, bob, "https://example.com/feed")
+
+	// feeds.user_id is ON DELETE RESTRICT, so the store refuses too.
+	if err := s.DBQueries.DeleteUser(ctx, bob.ID); err == nil {
+		t.Fatal("DeleteUser succeeded for a user who owns feeds")
+	}
+
+	err := HandleDeleteUser(s, types.Command{Name: "deleteuser", Args: []string{"--yes", "bob"}}, alice)
+	if err == nil {
+		t.Fatal("deleteuser without --reassign-to or --remove-feeds succeeded")
+	}
+	if _, err := s.DBQueries.GetUser(ctx, "bob"); err != nil {
+		t.Fatalf("bob is gone after a refused deleteuser: %v", err)
+	}
+
+	err = HandleDeleteUser(s, types.Command{Name: "deleteuser", Args: []string{"--yes", "--reassign-to", "alice", "bob"}}, alice)
+	if err != nil {
+		t.Fatalf("deleteuser --reassign-to: %v", err)
+	}
+	if _, err := s.DBQueries.GetUser(ctx, "bob"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetUser(bob) after deleteuser: got %v, want sql.ErrNoRows", err)
+	}
+	kept, err := s.DBQueries.GetFeed(ctx, feed.ID)
+	if err != nil {
+		t.Fatalf("feed is gone after reassigning it: %v", err)
+	}
+	if kept.UserID != alice.ID {
+		t.Errorf("feed belongs to %v, want alice (%v)", kept.UserID, alice.ID)
+	}
+	// bob followed his own feed; the follow goes with him.
+	follows, err := s.DBQueries.ListAllFeedFollows(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(follows) != 0 {
+		t.Errorf("got %d follows after deleting their only user, want 0", len(follows))
+	}
+}
+
+func TestRemoveFeedCascades(t *testing.T) {
+	s := newTestState(t)
+	ctx := context.Background()
+	alice := mustCreateUser(t, s, "alice")
+	feed := mustCreateFeed(t, s, alice, "https://example.com/feed")
+	other := mustCreateFeed(t, s, alice, "https://example.com/other")
+
+	parsed := mustParseFeed(t)
+	processFeed(s, parsed, &feed, defaultMinInterval, defaultMaxInterval)
+
+	enclosures, err := s.DBQueries.ListAllPostEnclosures(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(enclosures) != 1 {
+		t.Fatalf("got %d enclosures, want 1", len(enclosures))
+	}
+	_, err = s.DBQueries.UpsertDownload(ctx, database.UpsertDownloadParams{
+		UserID:      alice.ID,
+		EnclosureID: enclosures[0].ID,
+		Path:        "/tmp/1.mp3",
+		Size:        100,
+		Sha256:      "00",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = HandleRemoveFeed(s, types.Command{Name: "removefeed", Args: []string{"--yes", feed.Url}}, alice)
+	if err != nil {
+		t.Fatalf("removefeed: %v", err)
+	}
+
+	posts, _ := s.DBQueries.ListAllPosts(ctx)
+	enclosures, _ = s.DBQueries.ListAllPostEnclosures(ctx)
+	downloads, _ := s.DBQueries.ListAllDownloads(ctx)
+	follows, _ := s.DBQueries.ListAllFeedFollows(ctx)
+	if len(posts) != 0 || len(enclosures) != 0 || len(downloads) != 0 {
+		t.Errorf("after removefeed: %d posts, %d enclosures, %d downloads, want none",
+			len(posts), len(enclosures), len(downloads))
+	}
+	if len(follows) != 1 || follows[0].FeedID != other.ID {
+		t.Errorf("after removefeed: follows = %+v, want only the follow of the other feed", follows)
+	}
+}
+
+func TestWithTxRollsBack(t *testing.T) {
+	s := newTestState(t)
+	ctx := context.Background()
+	alice := mustCreateUser(t, s, "alice")
+	failure := errors.New("failure")
+
+	err := s.DBQueries.WithTx(ctx, func(q database.Querier) error {
+		if _, err := q.RenameUser(ctx, database.RenameUserParams{ID: alice.ID, Name: "carol"}); err != nil {
+			return err
+		}
+		if _, err := q.GetUser(ctx, "carol"); err != nil {
+			t.Errorf("rename not visible inside the transaction: %v", err)
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("WithTx returned %v, want %v", err, failure)
+	}
+	if _, err := s.DBQueries.GetUser(ctx, "alice"); err != nil {
+		t.Errorf("rename was not rolled back: %v", err)
+	}
+
+	err = s.DBQueries.WithTx(ctx, func(q database.Querier) error {
+		_, err := q.RenameUser(ctx, database.RenameUserParams{ID: alice.ID, Name: "carol"})
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.DBQueries.GetUser(ctx, "carol"); err != nil {
+		t.Errorf("rename was not committed: %v", err)
+	}
+}
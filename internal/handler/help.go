@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Shubham-Hazra/blog-aggregator/pkg/types"
+)
+
+// commandHelp lists every command with a one-line usage, in the order
+// help prints them.
+var commandHelp = []struct {
+	usage       string
+	description string
+}{
+	{"register <name>", "create a user, the first one becomes admin"},
+	{"login <name>", "log in as a user"},
+	{"passwd", "set or change your password"},
+	{"users", "list users"},
+	{"renameuser <name> <new_name>", "rename a user"},
+	{"deactivate [--yes] <name>", "stop a user from logging in"},
+	{"reactivate <name>", "let a deactivated user log in again (admin)"},
+	{"setrole <name> admin|member", "change a user's role (admin)"},
+	{"deleteuser [--reassign-to <name> | --remove-feeds] [--yes] <name>", "delete a user (admin)"},
+	{"addfeed [--no-fetch] <name> <url>", "add a feed and follow it"},
+	{"checkfeed <url>", "check whether a URL is a usable feed without adding it"},
+	{"feeds", "list all feeds"},
+	{"renamefeed <url> <new_name>", "rename a feed you added"},
+	{"setfeedurl [--no-fetch] [--yes] <url> <new_url>", "change the URL of a feed you added"},
+	{"removefeed [--yes] <url>", "remove a feed you added, with its posts"},
+	{"follow <url>", "follow a feed"},
+	{"unfollow <url>", "unfollow a feed"},
+	{"following", "list the feeds you follow"},
+	{"browse [--author <name>] [--category <name>] [limit]", "show recent posts of the feeds you follow"},
+	{"episodes [limit]", "show recent podcast episodes of the feeds you follow"},
+	{"download [--dir <path>] (<post_url> | --new [--limit <n>])", "download podcast episodes and attachments"},
+	{"agg [--record <dir> | --replay <dir>] [options] <time_between_reqs>", "fetch feeds continuously"},
+	{"serve [--listen <addr>] [--renew-every <duration>] <public_base_url>", "receive WebSub pushes and keep subscriptions alive"},
+	{"reset [--yes] [--backup <file>] (--all | --posts | --user <name>)", "delete data (admin)"},
+	{"backup <file>", "write all data to a compressed archive (admin)"},
+	{"restore <file>", "load an archive into an empty database"},
+	{"migrate [--yes] up|down|status", "manage the database schema (admin, except status and setting up a new database)"},
+	{"help", "show this help"},
+}
+
+// PrintHelp writes the list of commands and the ways to configure gator.
+// It needs neither a config file nor a database.
+func PrintHelp(w io.Writer) {
+	fmt.Fprintln(w, "usage: gator [--config <path>] <command> [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, c := range commandHelp {
+		fmt.Fprintf(w, "  %s\n      %s\n", c.usage, c.description)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Configuration is read from --config, or else from")
+	fmt.Fprintln(w, "$XDG_CONFIG_HOME/gator/config.json or ~/.gatorconfig.json.")
+	fmt.Fprintln(w, "Environment variables override it:")
+	fmt.Fprintln(w, "  GATOR_DB_URL  database to use, a Postgres URL or sqlite:<path>")
+	fmt.Fprintln(w, "  GATOR_USER    act as this user without logging in (users without a password only)")
+}
+
+// HandleHelp prints the list of commands
+func HandleHelp(s *State, cmd types.Command) error {
+	PrintHelp(os.Stdout)
+	return nil
+}
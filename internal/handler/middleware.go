@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"fmt"
+
 	"github.com/Shubham-Hazra/blog-aggregator/internal/database"
 	"github.com/Shubham-Hazra/blog-aggregator/pkg/types"
 )
@@ -13,4 +15,13 @@ func middlewareLoggedIn(handler func(s *State, cmd types.Command, user database.
 		}
 		return handler(s, cmd, user)
 	}
-}
\ No newline at end of file
+}
+
+func middlewareAdmin(handler func(s *State, cmd types.Command, user database.User) error) func(*State, types.Command) error {
+	return middlewareLoggedIn(func(s *State, cmd types.Command, user database.User) error {
+		if !isAdmin(user) {
+			return fmt.Errorf("%s can only be run by an admin", cmd.Name)
+		}
+		return handler(s, cmd, user)
+	})
+}
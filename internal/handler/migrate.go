@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/Shubham-Hazra/blog-aggregator/internal/database"
+	"github.com/Shubham-Hazra/blog-aggregator/internal/migrate"
+	"github.com/Shubham-Hazra/blog-aggregator/pkg/types"
+)
+
+// migrateAdminVersion is the first schema version with users.role and
+// users.deactivated_at, which middlewareAdmin needs to look up an admin.
+const migrateAdminVersion = 15
+
+// HandleMigrate applies, rolls back or lists the embedded schema
+// migrations. Applying and rolling back need an admin, except that a
+// database without one can always be migrated up.
+func HandleMigrate(s *State, cmd types.Command) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	yes := flags.Bool("yes", false, "confirm rolling back a migration")
+	if err := flags.Parse(cmd.Args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: migrate [--yes] up|down|status")
+	}
+
+	ctx := context.Background()
+	migrator, err := s.DBQueries.Migrator()
+	if err != nil {
+		return err
+	}
+
+	switch flags.Arg(0) {
+	case "up":
+		open, err := hasNoAdmin(ctx, s, migrator)
+		if err != nil {
+			return err
+		}
+		if open {
+			return migrateUp(ctx, migrator)
+		}
+		return middlewareAdmin(func(s *State, cmd types.Command, user database.User) error {
+			return migrateUp(ctx, migrator)
+		})(s, cmd)
+	case "down":
+		if !*yes {
+			return errors.New("rolling back a migration can drop tables and everything in them, run migrate --yes down to go ahead")
+		}
+		return middlewareAdmin(func(s *State, cmd types.Command, user database.User) error {
+			m, err := migrator.Down(ctx)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Rolled back %s\n", m.Name)
+			return nil
+		})(s, cmd)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			if status.Applied {
+				fmt.Printf("  applied %s  %s\n", status.AppliedAt.Local().Format("2006-01-02 15:04"), status.Name)
+			} else {
+				fmt.Printf("  pending %-16s  %s\n", "", status.Name)
+			}
+		}
+	default:
+		return errors.New("usage: migrate [--yes] up|down|status")
+	}
+	return nil
+}
+
+// hasNoAdmin reports whether nobody could log in as an admin to migrate
+// the database, because it is new, predates roles or has no active
+// admin left.
+func hasNoAdmin(ctx context.Context, s *State, migrator *migrate.Migrator) (bool, error) {
+	version, err := migrator.Version(ctx)
+	if err != nil {
+		return false, err
+	}
+	if version < migrateAdminVersion {
+		return true, nil
+	}
+	admins, err := s.DBQueries.CountAdmins(ctx)
+	if err != nil {
+		return false, err
+	}
+	return admins == 0, nil
+}
+
+func migrateUp(ctx context.Context, migrator *migrate.Migrator) error {
+	applied := 0
+	err := migrator.Up(ctx, func(m migrate.Migration) {
+		fmt.Printf("Applied %s\n", m.Name)
+		applied++
+	})
+	if err != nil {
+		return err
+	}
+	if applied == 0 {
+		fmt.Println("Schema is up to date")
+	}
+	return nil
+}
+
+// CheckSchema makes sure every embedded migration has been applied, so
+// commands don't fail halfway with confusing SQL errors.
+func CheckSchema(s *State) error {
+	migrator, err := s.DBQueries.Migrator()
+	if err != nil {
+		return err
+	}
+
+	err = migrator.Check(context.Background())
+	var behind *migrate.BehindError
+	if errors.As(err, &behind) {
+		return fmt.Errorf("%w, run \"gator migrate up\" first", behind)
+	} else if err != nil {
+		return fmt.Errorf("could not check database schema: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/Shubham-Hazra/blog-aggregator/internal/config"
+	"github.com/Shubham-Hazra/blog-aggregator/internal/store"
+	"github.com/Shubham-Hazra/blog-aggregator/pkg/types"
+)
+
+func TestMigrateNeedsAnAdmin(t *testing.T) {
+	db, err := store.Open("sqlite:" + filepath.Join(t.TempDir(), "gator.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	s, err := NewState(&config.Config{}, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	migrator, err := db.Migrator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	version := func() int64 {
+		t.Helper()
+		v, err := migrator.Version(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	migrate := func(user string, args ...string) error {
+		s.Config.USER_NAME = user
+		return HandleMigrate(s, types.Command{Name: "migrate", Args: args})
+	}
+
+	// Nobody can log in to a new database.
+	if err := migrate("", "up"); err != nil {
+		t.Fatalf("migrate up on a new database: %v", err)
+	}
+	if err := migrate("", "status"); err != nil {
+		t.Fatalf("migrate status: %v", err)
+	}
+	mustCreateUser(t, s, "alice") // the first user is the admin
+	mustCreateUser(t, s, "bob")
+	latest := migrator.Latest()
+
+	if err := migrate("bob", "--yes", "down"); err == nil {
+		t.Error("a member rolled back a migration")
+	}
+	if err := migrate("", "--yes", "down"); err == nil {
+		t.Error("rolled back a migration without logging in")
+	}
+	if err := migrate("alice", "down"); err == nil {
+		t.Error("rolled back a migration without --yes")
+	}
+	if err := migrate("bob", "up"); err == nil {
+		t.Error("a member ran migrate up")
+	}
+	if v := version(); v != latest {
+		t.Fatalf("schema at version %d after refused migrations, want %d", v, latest)
+	}
+
+	if err := migrate("alice", "--yes", "down"); err != nil {
+		t.Fatalf("admin rolling back: %v", err)
+	}
+	if v := version(); v != latest-1 {
+		t.Fatalf("schema at version %d after rolling back, want %d", v, latest-1)
+	}
+	if err := migrate("alice", "up"); err != nil {
+		t.Fatalf("admin migrating up: %v", err)
+	}
+	if v := version(); v != latest {
+		t.Errorf("schema at version %d after migrating up, want %d", v, latest)
+	}
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// stdin is shared by every prompt so that answers piped in on separate
+// lines aren't swallowed by an earlier prompt's buffer.
+var stdin = bufio.NewReader(os.Stdin)
+
+// confirm asks a yes/no question on stdin and reports whether the user
+// agreed. Anything other than "y" or "yes", including end of input, is
+// treated as a refusal.
+func confirm(question string) (bool, error) {
+	fmt.Printf("%s [y/N]: ", question)
+	answer, err := stdin.ReadString('\n')
+	if err != nil && answer == "" {
+		fmt.Println()
+		return false, nil
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+// readPassword prompts for a password without echoing it when stdin is
+// a terminal, and reads a plain line otherwise so scripts can pipe it in.
+// Like an empty line, end of input is an empty password.
+func readPassword(prompt string) (string, error) {
+	fmt.Print(prompt)
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		password, err := term.ReadPassword(fd)
+		fmt.Println()
+		if err != nil {
+			return "", fmt.Errorf("could not read password: %w", err)
+		}
+		return string(password), nil
+	}
+
+	line, err := stdin.ReadString('\n')
+	if errors.Is(err, io.EOF) {
+		fmt.Println()
+	} else if err != nil {
+		return "", fmt.Errorf("could not read password: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// promptNewPassword asks for a password twice. An empty password means
+// the account is not protected.
+func promptNewPassword() (string, error) {
+	password, err := readPassword("New password (leave empty for none): ")
+	if err != nil {
+		return "", err
+	}
+	if password == "" {
+		return "", nil
+	}
+	repeated, err := readPassword("Repeat password: ")
+	if err != nil {
+		return "", err
+	}
+	if password != repeated {
+		return "", errors.New("passwords do not match")
+	}
+	return password, nil
+}
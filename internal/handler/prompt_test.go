@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"testing"
+
+	"golang.org/x/term"
+)
+
+func TestPromptNewPasswordPiped(t *testing.T) {
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		t.Skip("stdin is a terminal")
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"no input", "", "", false},
+		{"empty line", "\n", "", false},
+		{"password twice", "secret\nsecret\n", "secret", false},
+		{"missing final newline", "secret\nsecret", "secret", false},
+		{"windows line endings", "secret\r\nsecret\r\n", "secret", false},
+		{"mismatch", "secret\nother\n", "", true},
+		{"repeat missing", "secret\n", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved := stdin
+			defer func() { stdin = saved }()
+			stdin = bufio.NewReader(strings.NewReader(tt.input))
+
+			got, err := promptNewPassword()
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("promptNewPassword() = %q, %v, want %q (error: %v)", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,18 +1,42 @@
 package handler
 
 import (
+	"fmt"
+
 	"github.com/Shubham-Hazra/blog-aggregator/internal/config"
-	"github.com/Shubham-Hazra/blog-aggregator/internal/database"
+	"github.com/Shubham-Hazra/blog-aggregator/internal/store"
+	"github.com/Shubham-Hazra/blog-aggregator/pkg/rss"
 )
 
 type State struct {
 	Config    *config.Config
-	DBQueries *database.Queries
+	DBQueries store.Store
+	Fetcher   rss.FeedFetcher
 }
 
-func NewState(config *config.Config, queries *database.Queries) *State {
+func NewState(config *config.Config, queries store.Store) (*State, error) {
+	fetcher, err := newFetcher(config.RECORD_DIR, config.REPLAY_DIR)
+	if err != nil {
+		return nil, err
+	}
 	return &State{
 		Config:    config,
 		DBQueries: queries,
+		Fetcher:   fetcher,
+	}, nil
+}
+
+// newFetcher fetches feeds over HTTP, saving the responses to recordDir
+// if set, or serves them from replayDir without touching the network.
+func newFetcher(recordDir, replayDir string) (rss.FeedFetcher, error) {
+	switch {
+	case recordDir != "" && replayDir != "":
+		return nil, fmt.Errorf("feeds can either be recorded or replayed, not both")
+	case replayDir != "":
+		return rss.NewReplayer(replayDir), nil
+	case recordDir != "":
+		return rss.NewRecorder(rss.NewFetcher(rss.DefaultFetcherOptions()), recordDir), nil
+	default:
+		return rss.NewFetcher(rss.DefaultFetcherOptions()), nil
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,274 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/Shubham-Hazra/blog-aggregator/internal/database"
+	"github.com/Shubham-Hazra/blog-aggregator/pkg/types"
+)
+
+const (
+	roleAdmin  = "admin"
+	roleMember = "member"
+)
+
+// HandleSetRole makes a user an admin or a regular member
+func HandleSetRole(s *State, cmd types.Command, admin database.User) error {
+	if err := validateArgCount(cmd.Args, 2, "setrole"); err != nil {
+		return err
+	}
+
+	userName, role := cmd.Args[0], cmd.Args[1]
+	if role != roleAdmin && role != roleMember {
+		return fmt.Errorf("unknown role %q, use %s or %s", role, roleAdmin, roleMember)
+	}
+
+	ctx := context.Background()
+	user, err := getUserByName(s, userName)
+	if err != nil {
+		return err
+	}
+	if user.Role == role {
+		fmt.Printf("%s is already %s\n", user.Name, role)
+		return nil
+	}
+	if err := ensureAnotherAdmin(s, user); err != nil {
+		return err
+	}
+
+	err = s.DBQueries.SetUserRole(ctx, database.SetUserRoleParams{
+		ID:   user.ID,
+		Role: role,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to change role: %w", err)
+	}
+
+	fmt.Printf("%s is now %s\n", user.Name, role)
+	return nil
+}
+
+func isAdmin(user database.User) bool {
+	return user.Role == roleAdmin
+}
+
+func getUserByName(s *State, userName string) (database.User, error) {
+	user, err := s.DBQueries.GetUser(context.Background(), userName)
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.User{}, fmt.Errorf("user %s is not registered", userName)
+	}
+	return user, err
+}
+
+// getManagedUser looks a user up by name and makes sure current is
+// allowed to manage the account, which is the case for the account
+// itself and for admins.
+func getManagedUser(s *State, userName string, current database.User) (database.User, error) {
+	user, err := getUserByName(s, userName)
+	if err != nil {
+		return database.User{}, err
+	}
+	if user.ID != current.ID && !isAdmin(current) {
+		return database.User{}, fmt.Errorf("only %s or an admin can change this account", user.Name)
+	}
+	return user, nil
+}
+
+// ensureAnotherAdmin refuses changes to user that would leave the
+// database without any active admin. Deactivated admins don't count, so
+// changing one of them is always fine.
+func ensureAnotherAdmin(s *State, user database.User) error {
+	if !isAdmin(user) || user.DeactivatedAt.Valid {
+		return nil
+	}
+	admins, err := s.DBQueries.CountAdmins(context.Background())
+	if err != nil {
+		return err
+	}
+	if admins <= 1 {
+		return errors.New("this is the only admin, make someone else an admin first")
+	}
+	return nil
+}
+
+// HandleDeleteUser removes a user account. Feeds the user added are
+// either handed over to another user or removed along with their posts.
+func HandleDeleteUser(s *State, cmd types.Command, admin database.User) error {
+	flags := flag.NewFlagSet("deleteuser", flag.ContinueOnError)
+	reassignTo := flags.String("reassign-to", "", "hand the user's feeds over to this user")
+	removeFeeds := flags.Bool("remove-feeds", false, "remove the user's feeds, even if others follow them")
+	yes := flags.Bool("yes", false, "do not ask for confirmation")
+	if err := flags.Parse(cmd.Args); err != nil {
+		return err
+	}
+	if err := validateArgCount(flags.Args(), 1, "deleteuser"); err != nil {
+		return err
+	}
+	if *reassignTo != "" && *removeFeeds {
+		return errors.New("use either --reassign-to or --remove-feeds, not both")
+	}
+
+	ctx := context.Background()
+	user, err := getUserByName(s, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	if err := ensureAnotherAdmin(s, user); err != nil {
+		return err
+	}
+
+	feedCount, err := s.DBQueries.CountFeedsForUser(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	var heir database.User
+	question := fmt.Sprintf("Delete user %s?", user.Name)
+	switch {
+	case *reassignTo != "":
+		heir, err = getUserByName(s, *reassignTo)
+		if err != nil {
+			return err
+		}
+		if heir.ID == user.ID {
+			return errors.New("cannot reassign feeds to the user being deleted")
+		}
+		question = fmt.Sprintf("Delete user %s and hand %d feed(s) over to %s?", user.Name, feedCount, heir.Name)
+	case *removeFeeds:
+		question = fmt.Sprintf("Delete user %s and remove %d feed(s) with all their posts?", user.Name, feedCount)
+	case feedCount > 0:
+		return fmt.Errorf("%s added %d feed(s), use --reassign-to <user> or --remove-feeds", user.Name, feedCount)
+	}
+
+	if !*yes {
+		ok, err := confirm(question)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errAborted
+		}
+	}
+
+	err = s.DBQueries.WithTx(ctx, func(q database.Querier) error {
+		if *reassignTo != "" {
+			err := q.ReassignFeeds(ctx, database.ReassignFeedsParams{
+				ToUserID:   heir.ID,
+				FromUserID: user.ID,
+			})
+			if err != nil {
+				return err
+			}
+		} else if err := q.DeleteFeedsForUser(ctx, user.ID); err != nil {
+			return err
+		}
+		return q.DeleteUser(ctx, user.ID)
+	})
+	if err != nil {
+		return fmt.Errorf("unable to delete user: %w", err)
+	}
+
+	fmt.Printf("Deleted user %s\n", user.Name)
+	return nil
+}
+
+// HandleRenameUser changes a user's name. Members can only rename
+// themselves.
+func HandleRenameUser(s *State, cmd types.Command, current database.User) error {
+	if err := validateArgCount(cmd.Args, 2, "renameuser"); err != nil {
+		return err
+	}
+
+	oldName, newName := cmd.Args[0], cmd.Args[1]
+	user, err := getManagedUser(s, oldName, current)
+	if err != nil {
+		return err
+	}
+	if _, err := s.DBQueries.GetUser(context.Background(), newName); err == nil {
+		return fmt.Errorf("a user named %s already exists", newName)
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	renamed, err := s.DBQueries.RenameUser(context.Background(), database.RenameUserParams{
+		ID:   user.ID,
+		Name: newName,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to rename user: %w", err)
+	}
+
+	fmt.Printf("Renamed user %s to %s\n", oldName, renamed.Name)
+	return nil
+}
+
+// HandleDeactivate locks a user out without deleting anything they
+// added. Members can only deactivate themselves.
+func HandleDeactivate(s *State, cmd types.Command, current database.User) error {
+	flags := flag.NewFlagSet("deactivate", flag.ContinueOnError)
+	yes := flags.Bool("yes", false, "do not ask for confirmation")
+	if err := flags.Parse(cmd.Args); err != nil {
+		return err
+	}
+	if err := validateArgCount(flags.Args(), 1, "deactivate"); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	user, err := getManagedUser(s, flags.Arg(0), current)
+	if err != nil {
+		return err
+	}
+	if user.DeactivatedAt.Valid {
+		fmt.Printf("%s is already deactivated\n", user.Name)
+		return nil
+	}
+	if err := ensureAnotherAdmin(s, user); err != nil {
+		return err
+	}
+
+	if !*yes {
+		ok, err := confirm(fmt.Sprintf("Deactivate %s? They will be logged out and unable to log in.", user.Name))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errAborted
+		}
+	}
+
+	err = s.DBQueries.WithTx(ctx, func(q database.Querier) error {
+		if err := q.DeactivateUser(ctx, user.ID); err != nil {
+			return err
+		}
+		return q.DeleteSessionsForUser(ctx, user.ID)
+	})
+	if err != nil {
+		return fmt.Errorf("unable to deactivate user: %w", err)
+	}
+
+	fmt.Printf("Deactivated user %s\n", user.Name)
+	return nil
+}
+
+// HandleReactivate lets a deactivated user log in again
+func HandleReactivate(s *State, cmd types.Command, admin database.User) error {
+	if err := validateArgCount(cmd.Args, 1, "reactivate"); err != nil {
+		return err
+	}
+
+	user, err := getUserByName(s, cmd.Args[0])
+	if err != nil {
+		return err
+	}
+	if err := s.DBQueries.ReactivateUser(context.Background(), user.ID); err != nil {
+		return fmt.Errorf("unable to reactivate user: %w", err)
+	}
+
+	fmt.Printf("Reactivated user %s\n", user.Name)
+	return nil
+}
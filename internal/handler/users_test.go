@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Shubham-Hazra/blog-aggregator/internal/database"
+	"github.com/Shubham-Hazra/blog-aggregator/pkg/types"
+)
+
+func TestDeactivatedAdminsDoNotCount(t *testing.T) {
+	s := newTestState(t)
+	ctx := context.Background()
+	alice := mustCreateUser(t, s, "alice")
+	bob := mustCreateUser(t, s, "bob")
+	for _, user := range []database.User{alice, bob} {
+		if err := s.DBQueries.SetUserRole(ctx, database.SetUserRoleParams{ID: user.ID, Role: roleAdmin}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.DBQueries.DeactivateUser(ctx, bob.ID); err != nil {
+		t.Fatal(err)
+	}
+	alice, _ = s.DBQueries.GetUser(ctx, "alice")
+
+	// alice is the only active admin.
+	err := HandleDeactivate(s, types.Command{Name: "deactivate", Args: []string{"--yes", "alice"}}, alice)
+	if err == nil {
+		t.Error("deactivated the only active admin")
+	}
+	err = HandleSetRole(s, types.Command{Name: "setrole", Args: []string{"alice", roleMember}}, alice)
+	if err == nil {
+		t.Error("demoted the only active admin")
+	}
+
+	// bob is an admin too, but a deactivated one.
+	err = HandleSetRole(s, types.Command{Name: "setrole", Args: []string{"bob", roleMember}}, alice)
+	if err != nil {
+		t.Errorf("demoting a deactivated admin: %v", err)
+	}
+	if err := s.DBQueries.SetUserRole(ctx, database.SetUserRoleParams{ID: bob.ID, Role: roleAdmin}); err != nil {
+		t.Fatal(err)
+	}
+	err = HandleDeleteUser(s, types.Command{Name: "deleteuser", Args: []string{"--yes", "bob"}}, alice)
+	if err != nil {
+		t.Errorf("deleting a deactivated admin: %v", err)
+	}
+}
@@ -0,0 +1,279 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Shubham-Hazra/blog-aggregator/internal/database"
+	"github.com/Shubham-Hazra/blog-aggregator/pkg/rss"
+	"github.com/Shubham-Hazra/blog-aggregator/pkg/types"
+	"github.com/Shubham-Hazra/blog-aggregator/pkg/websub"
+)
+
+// Subscription states stored in websub_subscriptions.state.
+const (
+	websubStateNew     = "new"
+	websubStatePending = "pending"
+	websubStateActive  = "active"
+	websubStateDenied  = "denied"
+)
+
+const (
+	websubLease = 10 * 24 * time.Hour
+	// websubRenewBefore is how long before a lease expires it is renewed.
+	websubRenewBefore = 24 * time.Hour
+	// websubRetryAfter is how long an unverified request waits before it
+	// is sent again.
+	websubRetryAfter   = time.Hour
+	websubCallbackPath = "/websub/"
+
+	// Hubs post small notifications; anything slower than this is a
+	// client holding a connection open.
+	websubReadHeaderTimeout = 10 * time.Second
+	websubReadTimeout       = time.Minute
+	websubIdleTimeout       = 2 * time.Minute
+	// websubShutdownTimeout is how long in-flight callbacks get to finish
+	// when serve is stopped.
+	websubShutdownTimeout = 10 * time.Second
+)
+
+// HandleServe runs the WebSub callback server, subscribing to the hubs
+// advertised by feeds and saving the content they push.
+func HandleServe(s *State, cmd types.Command) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	listen := flags.String("listen", ":8080", "address to accept hub callbacks on")
+	renewEvery := flags.Duration("renew-every", time.Minute, "how often to check for subscriptions to (re)send")
+	if err := flags.Parse(cmd.Args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: serve [--listen <addr>] [--renew-every <duration>] <public_base_url>")
+	}
+	baseURL := strings.TrimRight(flags.Arg(0), "/")
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	mux := http.NewServeMux()
+	mux.Handle(websubCallbackPath, &websub.CallbackHandler{Subscriber: &websubSubscriber{state: s}})
+	server := &http.Server{
+		Addr:              *listen,
+		Handler:           mux,
+		ReadHeaderTimeout: websubReadHeaderTimeout,
+		ReadTimeout:       websubReadTimeout,
+		IdleTimeout:       websubIdleTimeout,
+	}
+
+	go func() {
+		ticker := time.NewTicker(*renewEvery)
+		defer ticker.Stop()
+		for {
+			renewWebSubSubscriptions(s, baseURL)
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	fmt.Printf("Accepting WebSub callbacks on %s at %s%s\n", *listen, baseURL, websubCallbackPath)
+	return serveUntilDone(ctx, server)
+}
+
+// serveUntilDone runs server until it fails or ctx is cancelled, in which
+// case it is shut down gracefully.
+func serveUntilDone(ctx context.Context, server *http.Server) error {
+	errc := make(chan error, 1)
+	go func() {
+		errc <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), websubShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("stopping the callback server: %w", err)
+	}
+	return nil
+}
+
+// renewWebSubSubscriptions sends subscription requests for new
+// subscriptions, unverified ones that timed out and leases about to expire.
+func renewWebSubSubscriptions(s *State, baseURL string) {
+	subs, err := s.DBQueries.GetWebSubSubscriptionsToRenew(context.Background(), database.GetWebSubSubscriptionsToRenewParams{
+		RetrySeconds: int32(websubRetryAfter / time.Second),
+		RenewSeconds: int32(websubRenewBefore / time.Second),
+	})
+	if err != nil {
+		log.Printf("Error listing WebSub subscriptions: %v\n", err)
+		return
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	for _, sub := range subs {
+		// Mark the request as pending first, so a hub that fails is
+		// retried after websubRetryAfter rather than on every tick.
+		err := s.DBQueries.SetWebSubSubscriptionState(context.Background(), database.SetWebSubSubscriptionStateParams{
+			ID:    sub.ID,
+			State: websubStatePending,
+		})
+		if err != nil {
+			log.Printf("Error updating WebSub subscription %d: %v\n", sub.ID, err)
+			continue
+		}
+
+		req := websub.Request{
+			Hub:      sub.HubUrl,
+			Topic:    sub.TopicUrl,
+			Callback: baseURL + websubCallbackPath + strconv.Itoa(int(sub.ID)),
+			Secret:   sub.Secret,
+			Lease:    websubLease,
+		}
+		if err := req.Send(context.Background(), client, websub.ModeSubscribe); err != nil {
+			log.Printf("Error subscribing to %s: %v\n", sub.TopicUrl, err)
+			continue
+		}
+		log.Printf("Requested WebSub subscription to %s at %s\n", sub.TopicUrl, sub.HubUrl)
+	}
+}
+
+// saveHubSubscription records the hub a feed advertises so that the
+// server started by serve subscribes to it.
+func saveHubSubscription(s *State, feed *rss.Feed, dbFeed *database.Feed) {
+	hubs := feed.HubURLs()
+	if len(hubs) == 0 {
+		return
+	}
+	topic := feed.SelfURL()
+	if topic == "" {
+		topic = dbFeed.Url
+	}
+
+	secret, err := newWebSubSecret()
+	if err != nil {
+		log.Printf("Error generating WebSub secret: %v\n", err)
+		return
+	}
+
+	err = s.DBQueries.UpsertWebSubSubscription(context.Background(), database.UpsertWebSubSubscriptionParams{
+		FeedID:   dbFeed.ID,
+		HubUrl:   hubs[0],
+		TopicUrl: topic,
+		Secret:   secret,
+	})
+	if err != nil {
+		log.Printf("Error saving WebSub hub for feed %s: %v\n", dbFeed.Url, err)
+	}
+}
+
+// hasActiveWebSubSubscription reports whether updates to feed are pushed
+// to us, in which case it only needs to be polled occasionally.
+func hasActiveWebSubSubscription(s *State, feed *database.Feed) bool {
+	sub, err := s.DBQueries.GetWebSubSubscriptionForFeed(context.Background(), feed.ID)
+	return err == nil && sub.State == websubStateActive
+}
+
+func newWebSubSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// websubSubscriber backs the callback handler with websub_subscriptions.
+type websubSubscriber struct {
+	state *State
+}
+
+func (w *websubSubscriber) lookup(ctx context.Context, id string) (database.WebsubSubscription, error) {
+	subID, err := strconv.Atoi(id)
+	if err != nil {
+		return database.WebsubSubscription{}, websub.ErrUnknownSubscription
+	}
+	sub, err := w.state.DBQueries.GetWebSubSubscription(ctx, int32(subID))
+	if err == sql.ErrNoRows {
+		return sub, websub.ErrUnknownSubscription
+	}
+	return sub, err
+}
+
+func (w *websubSubscriber) Subscription(ctx context.Context, id string) (*websub.Subscription, error) {
+	sub, err := w.lookup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &websub.Subscription{Topic: sub.TopicUrl, Secret: sub.Secret}, nil
+}
+
+func (w *websubSubscriber) Verified(ctx context.Context, id, mode string, lease time.Duration) error {
+	sub, err := w.lookup(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	switch mode {
+	case websub.ModeSubscribe:
+		if sub.State != websubStatePending && sub.State != websubStateActive {
+			return fmt.Errorf("subscription is %s", sub.State)
+		}
+		if lease <= 0 {
+			lease = websubLease
+		}
+		err = w.state.DBQueries.ActivateWebSubSubscription(ctx, database.ActivateWebSubSubscriptionParams{
+			LeaseSeconds: int32(lease / time.Second),
+			ID:           sub.ID,
+		})
+		if err == nil {
+			log.Printf("WebSub subscription to %s active for %v\n", sub.TopicUrl, lease)
+		}
+		return err
+	case websub.ModeDenied:
+		log.Printf("Hub %s denied subscription to %s\n", sub.HubUrl, sub.TopicUrl)
+		return w.state.DBQueries.SetWebSubSubscriptionState(ctx, database.SetWebSubSubscriptionStateParams{
+			ID:    sub.ID,
+			State: websubStateDenied,
+		})
+	default:
+		return fmt.Errorf("%s was not requested", mode)
+	}
+}
+
+func (w *websubSubscriber) Deliver(ctx context.Context, id, contentType string, body []byte) error {
+	sub, err := w.lookup(ctx, id)
+	if err != nil {
+		return err
+	}
+	dbFeed, err := w.state.DBQueries.GetFeed(ctx, sub.FeedID)
+	if err != nil {
+		return err
+	}
+
+	feed, err := rss.Parse(body, contentType)
+	if err != nil {
+		return err
+	}
+	feed.ResolveURLs(sub.TopicUrl)
+
+	log.Printf("Received %d item(s) for %s from hub\n", len(feed.Channel.Items), dbFeed.Url)
+	savePostsToDB(w.state, feed, &dbFeed)
+	return nil
+}
@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Shubham-Hazra/blog-aggregator/pkg/rss"
+	"github.com/Shubham-Hazra/blog-aggregator/pkg/websub"
+)
+
+const hubFeed = `<?xml version="1.0"?>
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">
+<channel>
+  <title>Pushed</title>
+  <link>https://example.com/</link>
+  <atom:link rel="hub" href="%HUB%"/>
+  <atom:link rel="self" href="https://example.com/feed"/>
+  <item>
+    <title>Pushed post</title>
+    <link>https://example.com/pushed</link>
+    <pubDate>Mon, 02 Jan 2006 15:04:05 GMT</pubDate>
+  </item>
+</channel>
+</rss>`
+
+// stubHub records subscription requests so the test can play the hub's
+// part of the protocol against the callback server.
+type stubHub struct {
+	requests chan url.Values
+}
+
+func (h *stubHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.requests <- r.PostForm
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func TestWebSubSubscription(t *testing.T) {
+	s := newTestState(t)
+	ctx := context.Background()
+	alice := mustCreateUser(t, s, "alice")
+	feed := mustCreateFeed(t, s, alice, "https://example.com/feed")
+
+	hub := &stubHub{requests: make(chan url.Values, 1)}
+	hubServer := httptest.NewServer(hub)
+	defer hubServer.Close()
+
+	mux := http.NewServeMux()
+	mux.Handle(websubCallbackPath, &websub.CallbackHandler{Subscriber: &websubSubscriber{state: s}})
+	callbackServer := httptest.NewServer(mux)
+	defer callbackServer.Close()
+
+	content := strings.ReplaceAll(hubFeed, "%HUB%", hubServer.URL)
+	parsed, err := rss.Parse([]byte(content), "application/rss+xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	saveHubSubscription(s, parsed, &feed)
+
+	// The subscribe request.
+	renewWebSubSubscriptions(s, callbackServer.URL)
+	var form url.Values
+	select {
+	case form = <-hub.requests:
+	default:
+		t.Fatal("no subscription request reached the hub")
+	}
+	if form.Get("hub.mode") != websub.ModeSubscribe || form.Get("hub.topic") != "https://example.com/feed" {
+		t.Errorf("hub got %v", form)
+	}
+	callback, secret := form.Get("hub.callback"), form.Get("hub.secret")
+	if !strings.HasPrefix(callback, callbackServer.URL+websubCallbackPath) || secret == "" {
+		t.Fatalf("hub got callback %q and secret %q", callback, secret)
+	}
+
+	// The hub's verification of intent.
+	query := url.Values{
+		"hub.mode":          {websub.ModeSubscribe},
+		"hub.topic":         {form.Get("hub.topic")},
+		"hub.challenge":     {"challenge-token"},
+		"hub.lease_seconds": {"86400"},
+	}
+	res, err := http.Get(callback + "?" + query.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK || string(body) != "challenge-token" {
+		t.Fatalf("verification answered %s %q, want the challenge echoed", res.Status, body)
+	}
+	if !hasActiveWebSubSubscription(s, &feed) {
+		t.Error("subscription is not active after verification")
+	}
+
+	deliver := func(signature string) {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodPost, callback, strings.NewReader(content))
+		req.Header.Set("Content-Type", "application/rss+xml")
+		req.Header.Set(websub.SignatureHeader, signature)
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		if res.StatusCode != http.StatusAccepted {
+			t.Errorf("delivery answered %s, want 202", res.Status)
+		}
+	}
+
+	// Content signed with another secret is dropped.
+	deliver(websub.Sign("not-the-secret", []byte(content)))
+	posts, err := s.DBQueries.ListAllPosts(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(posts) != 0 {
+		t.Fatalf("badly signed delivery saved %d post(s)", len(posts))
+	}
+
+	deliver(websub.Sign(secret, []byte(content)))
+	posts, err = s.DBQueries.ListAllPosts(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(posts) != 1 || posts[0].Url != "https://example.com/pushed" || posts[0].FeedID != feed.ID {
+		t.Errorf("after a signed delivery: posts = %+v, want the pushed post", posts)
+	}
+}
+
+func TestServeUntilDone(t *testing.T) {
+	// Find a free port for the server to listen on.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	server := &http.Server{
+		Addr: addr,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, "ok")
+		}),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- serveUntilDone(ctx, server)
+	}()
+
+	var resp *http.Response
+	for range 50 {
+		resp, err = http.Get("http://" + addr + "/")
+		if err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("server never came up: %v", err)
+	}
+	resp.Body.Close()
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("serveUntilDone after cancel = %v, want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server still running after its context was cancelled")
+	}
+}
+
+func TestServeUntilDoneListenError(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	server := &http.Server{Addr: l.Addr().String()}
+	if err := serveUntilDone(context.Background(), server); err == nil {
+		t.Error("serveUntilDone on a busy address returned nil")
+	}
+}
@@ -0,0 +1,391 @@
+// Package migrate applies goose-style SQL migrations. It keeps its
+// bookkeeping in goose's own version table, so databases migrated with
+// the goose CLI and with gator are interchangeable.
+package migrate
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Dialect holds the SQL for managing goose's version table, which is
+// the only part of migrating that differs between databases.
+type Dialect struct {
+	tableExists   string
+	createTable   string
+	seedVersion   string
+	insertVersion string
+	deleteVersion string
+}
+
+// Postgres is the dialect for PostgreSQL databases.
+var Postgres = Dialect{
+	tableExists: "SELECT to_regclass('goose_db_version') IS NOT NULL",
+	createTable: `CREATE TABLE IF NOT EXISTS goose_db_version (
+		id SERIAL PRIMARY KEY,
+		version_id BIGINT NOT NULL,
+		is_applied BOOLEAN NOT NULL,
+		tstamp TIMESTAMP DEFAULT now()
+	)`,
+	seedVersion: `INSERT INTO goose_db_version (version_id, is_applied)
+		SELECT 0, true WHERE NOT EXISTS (SELECT 1 FROM goose_db_version)`,
+	insertVersion: "INSERT INTO goose_db_version (version_id, is_applied) VALUES ($1, true)",
+	deleteVersion: "DELETE FROM goose_db_version WHERE version_id = $1",
+}
+
+// SQLite is the dialect for SQLite databases.
+var SQLite = Dialect{
+	tableExists: "SELECT EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'goose_db_version')",
+	createTable: `CREATE TABLE IF NOT EXISTS goose_db_version (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		version_id INTEGER NOT NULL,
+		is_applied INTEGER NOT NULL,
+		tstamp TIMESTAMP DEFAULT (datetime('now'))
+	)`,
+	seedVersion: `INSERT INTO goose_db_version (version_id, is_applied)
+		SELECT 0, 1 WHERE NOT EXISTS (SELECT 1 FROM goose_db_version)`,
+	insertVersion: "INSERT INTO goose_db_version (version_id, is_applied) VALUES (?, 1)",
+	deleteVersion: "DELETE FROM goose_db_version WHERE version_id = ?",
+}
+
+// Migration is a single numbered schema change.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      []string
+	Down    []string
+}
+
+// Status describes whether a migration has been applied.
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// BehindError is returned by Check when the database is missing
+// migrations that this build expects.
+type BehindError struct {
+	Current int64
+	Latest  int64
+}
+
+func (e *BehindError) Error() string {
+	return fmt.Sprintf("database schema is at version %d but this build needs version %d", e.Current, e.Latest)
+}
+
+// Migrator applies a fixed set of migrations to a database.
+type Migrator struct {
+	db         *sql.DB
+	dialect    Dialect
+	migrations []Migration
+}
+
+// New loads the migrations in the root of fsys. File names must start
+// with the version number followed by an underscore, as goose expects.
+func New(db *sql.DB, dialect Dialect, fsys fs.FS) (*Migrator, error) {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Migrator{db: db, dialect: dialect}
+	seen := make(map[int64]string)
+	for _, name := range names {
+		prefix, _, ok := strings.Cut(name, "_")
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if !ok || err != nil || version < 1 {
+			return nil, fmt.Errorf("migration %s: file name must start with a version number", name)
+		}
+		if other, dup := seen[version]; dup {
+			return nil, fmt.Errorf("migrations %s and %s share version %d", other, name, version)
+		}
+		seen[version] = name
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		up, down, err := parse(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %w", name, err)
+		}
+		m.migrations = append(m.migrations, Migration{
+			Version: version,
+			Name:    strings.TrimSuffix(path.Base(name), ".sql"),
+			Up:      up,
+			Down:    down,
+		})
+	}
+
+	sort.Slice(m.migrations, func(i, j int) bool {
+		return m.migrations[i].Version < m.migrations[j].Version
+	})
+	return m, nil
+}
+
+// Latest returns the version of the newest known migration.
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Version returns the highest applied migration, or 0 for a database
+// that has never been migrated.
+func (m *Migrator) Version(ctx context.Context) (int64, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var current int64
+	for version := range applied {
+		if version > current {
+			current = version
+		}
+	}
+	return current, nil
+}
+
+// Check returns a *BehindError if any known migration is not applied.
+func (m *Migrator) Check(ctx context.Context) error {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			current, err := m.Version(ctx)
+			if err != nil {
+				return err
+			}
+			return &BehindError{Current: current, Latest: m.Latest()}
+		}
+	}
+	return nil
+}
+
+// Status lists every known migration and whether it has been applied.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		appliedAt, ok := applied[migration.Version]
+		statuses = append(statuses, Status{
+			Migration: migration,
+			Applied:   ok,
+			AppliedAt: appliedAt,
+		})
+	}
+	return statuses, nil
+}
+
+// Up applies every pending migration in version order, each in its own
+// transaction, calling done after each one.
+func (m *Migrator) Up(ctx context.Context, done func(Migration)) error {
+	if err := m.ensureVersionTable(ctx); err != nil {
+		return err
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		err := m.run(ctx, migration.Up, func(tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, m.dialect.insertVersion, migration.Version)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("applying %s: %w", migration.Name, err)
+		}
+		if done != nil {
+			done(migration)
+		}
+	}
+	return nil
+}
+
+// ErrNoMigrations is returned by Down when nothing has been applied.
+var ErrNoMigrations = errors.New("no migrations to roll back")
+
+// Down rolls back the most recently applied migration.
+func (m *Migrator) Down(ctx context.Context) (Migration, error) {
+	current, err := m.Version(ctx)
+	if err != nil {
+		return Migration{}, err
+	}
+	if current == 0 {
+		return Migration{}, ErrNoMigrations
+	}
+
+	for _, migration := range m.migrations {
+		if migration.Version != current {
+			continue
+		}
+		err := m.run(ctx, migration.Down, func(tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, m.dialect.deleteVersion, migration.Version)
+			return err
+		})
+		if err != nil {
+			return Migration{}, fmt.Errorf("rolling back %s: %w", migration.Name, err)
+		}
+		return migration, nil
+	}
+	return Migration{}, fmt.Errorf("database is at version %d, which this build doesn't know", current)
+}
+
+// run executes statements and records the result in one transaction.
+func (m *Migrator) run(ctx context.Context, statements []string, record func(*sql.Tx) error) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	if err := record(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// applied returns the applied versions and when they were applied. Like
+// goose, the newest row for a version decides its state.
+func (m *Migrator) applied(ctx context.Context) (map[int64]time.Time, error) {
+	var exists bool
+	err := m.db.QueryRowContext(ctx, m.dialect.tableExists).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+	applied := make(map[int64]time.Time)
+	if !exists {
+		return applied, nil
+	}
+
+	rows, err := m.db.QueryContext(ctx,
+		"SELECT version_id, is_applied, tstamp FROM goose_db_version ORDER BY id DESC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	seen := make(map[int64]bool)
+	for rows.Next() {
+		var version int64
+		var isApplied bool
+		var tstamp sql.NullTime
+		if err := rows.Scan(&version, &isApplied, &tstamp); err != nil {
+			return nil, err
+		}
+		if seen[version] {
+			continue
+		}
+		seen[version] = true
+		if isApplied && version > 0 {
+			applied[version] = tstamp.Time
+		}
+	}
+	return applied, rows.Err()
+}
+
+// ensureVersionTable creates goose's version table the way goose does,
+// including its initial version 0 row.
+func (m *Migrator) ensureVersionTable(ctx context.Context) error {
+	_, err := m.db.ExecContext(ctx, m.dialect.createTable)
+	if err != nil {
+		return err
+	}
+	_, err = m.db.ExecContext(ctx, m.dialect.seedVersion)
+	return err
+}
+
+// parse splits a goose migration into its up and down statements.
+// Statements end with a semicolon at the end of a line, unless they are
+// wrapped in StatementBegin and StatementEnd annotations.
+func parse(source string) (up, down []string, err error) {
+	var section *[]string
+	var statement strings.Builder
+	inBlock := false
+
+	scanner := bufio.NewScanner(strings.NewReader(source))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if annotation, ok := strings.CutPrefix(trimmed, "-- +goose "); ok {
+			switch strings.TrimSpace(annotation) {
+			case "Up":
+				section = &up
+			case "Down":
+				section = &down
+			case "StatementBegin":
+				inBlock = true
+			case "StatementEnd":
+				inBlock = false
+				*section = appendStatement(*section, &statement)
+			}
+			continue
+		}
+		if section == nil {
+			continue
+		}
+
+		statement.WriteString(line)
+		statement.WriteByte('\n')
+		if !inBlock && strings.HasSuffix(trimmed, ";") && !strings.HasPrefix(trimmed, "--") {
+			*section = appendStatement(*section, &statement)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	if section == nil {
+		return nil, nil, errors.New("missing -- +goose Up annotation")
+	}
+	if strings.TrimSpace(statement.String()) != "" && !isComment(statement.String()) {
+		return nil, nil, errors.New("last statement is not terminated by a semicolon")
+	}
+	return up, down, nil
+}
+
+func appendStatement(statements []string, b *strings.Builder) []string {
+	statement := strings.TrimSpace(b.String())
+	b.Reset()
+	if statement == "" || isComment(statement) {
+		return statements
+	}
+	return append(statements, statement)
+}
+
+// isComment reports whether text consists only of SQL line comments.
+func isComment(text string) bool {
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "--") {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,326 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Shubham-Hazra/blog-aggregator/internal/database"
+	"github.com/Shubham-Hazra/blog-aggregator/internal/migrate"
+	"github.com/google/uuid"
+)
+
+// Memory is a Store that keeps everything in process memory. It follows
+// the Postgres schema's unique constraints, foreign keys and cascades,
+// so handlers behave the same against it as against a real database,
+// which makes it suitable for tests. Data is lost when the process exits.
+type Memory struct {
+	*memQueries
+}
+
+// NewMemory returns an empty in-memory store.
+func NewMemory() *Memory {
+	return &Memory{
+		memQueries: &memQueries{
+			mu: &sync.Mutex{},
+			d:  &memData{},
+		},
+	}
+}
+
+var _ Store = (*Memory)(nil)
+
+// memData holds the rows of every table. Rows are kept in insertion
+// order, which is also id order unless ids were restored explicitly.
+type memData struct {
+	users          []database.User
+	sessions       []database.Session
+	feeds          []database.Feed
+	feedFollows    []database.FeedFollow
+	posts          []database.Post
+	categories     []database.Category
+	postCategories []database.PostCategory
+	postEnclosures []database.PostEnclosure
+	downloads      []database.Download
+	subscriptions  []database.WebsubSubscription
+	sequences      map[string]int32
+}
+
+func (d *memData) clone() *memData {
+	c := &memData{
+		users:          append([]database.User(nil), d.users...),
+		sessions:       append([]database.Session(nil), d.sessions...),
+		feeds:          append([]database.Feed(nil), d.feeds...),
+		feedFollows:    append([]database.FeedFollow(nil), d.feedFollows...),
+		posts:          append([]database.Post(nil), d.posts...),
+		categories:     append([]database.Category(nil), d.categories...),
+		postCategories: append([]database.PostCategory(nil), d.postCategories...),
+		postEnclosures: append([]database.PostEnclosure(nil), d.postEnclosures...),
+		downloads:      append([]database.Download(nil), d.downloads...),
+		subscriptions:  append([]database.WebsubSubscription(nil), d.subscriptions...),
+		sequences:      make(map[string]int32, len(d.sequences)),
+	}
+	for table, value := range d.sequences {
+		c.sequences[table] = value
+	}
+	return c
+}
+
+// nextID returns the next value of a table's serial id sequence.
+func (d *memData) nextID(table string) int32 {
+	if d.sequences == nil {
+		d.sequences = make(map[string]int32)
+	}
+	d.sequences[table]++
+	return d.sequences[table]
+}
+
+// memQueries implements database.Querier on memData. Outside of a
+// transaction every call takes mu; inside one mu is nil because the
+// transaction already holds the store's lock.
+type memQueries struct {
+	mu *sync.Mutex
+	d  *memData
+}
+
+var _ database.Querier = (*memQueries)(nil)
+
+func (q *memQueries) lock() func() {
+	if q.mu == nil {
+		return func() {}
+	}
+	q.mu.Lock()
+	return q.mu.Unlock
+}
+
+// WithTx runs fn against a copy of the data that replaces the original
+// only if fn succeeds. Transactions are serialized with every other call.
+func (m *Memory) WithTx(ctx context.Context, fn func(q database.Querier) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tx := &memQueries{d: m.d.clone()}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	*m.d = *tx.d
+	return nil
+}
+
+// WithSnapshot runs fn against a copy of the data. Changes made by fn
+// are discarded.
+func (m *Memory) WithSnapshot(ctx context.Context, fn func(q database.Querier) error) error {
+	m.mu.Lock()
+	snapshot := &memQueries{d: m.d.clone()}
+	m.mu.Unlock()
+
+	return fn(snapshot)
+}
+
+func (m *Memory) Migrator() (*migrate.Migrator, error) {
+	return nil, errors.New("the in-memory store has no schema to migrate")
+}
+
+func (m *Memory) Driver() string {
+	return "memory"
+}
+
+func (m *Memory) Close() error {
+	return nil
+}
+
+// constraintError is returned when a write would violate the schema. The
+// wording matches SQLite's, so code that recognizes constraint errors by
+// their message treats both backends alike.
+type constraintError struct {
+	kind   string
+	column string
+}
+
+func (e *constraintError) Error() string {
+	return e.kind + " constraint failed: " + e.column
+}
+
+func uniqueViolation(column string) error {
+	return &constraintError{kind: "UNIQUE", column: column}
+}
+
+func foreignKeyViolation(column string) error {
+	return &constraintError{kind: "FOREIGN KEY", column: column}
+}
+
+func checkViolation(column string) error {
+	return &constraintError{kind: "CHECK", column: column}
+}
+
+// now stands in for CURRENT_TIMESTAMP, at the precision Postgres keeps.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+func nullNow() sql.NullTime {
+	return sql.NullTime{Time: now(), Valid: true}
+}
+
+func seconds(n int32) time.Duration {
+	return time.Duration(n) * time.Second
+}
+
+// after returns the time n seconds from now.
+func after(n int32) time.Time {
+	return now().Add(seconds(n))
+}
+
+// lessNullsFirst orders times ascending with NULLs first.
+func lessNullsFirst(a, b sql.NullTime) bool {
+	if !a.Valid || !b.Valid {
+		return !a.Valid && b.Valid
+	}
+	return a.Time.Before(b.Time)
+}
+
+// lessDescending orders times descending with NULLs first, like
+// Postgres does for ORDER BY ... DESC.
+func lessDescending(a, b sql.NullTime) bool {
+	if !a.Valid || !b.Valid {
+		return !a.Valid && b.Valid
+	}
+	return a.Time.After(b.Time)
+}
+
+func limit[T any](rows []T, n int32) []T {
+	if n >= 0 && int(n) < len(rows) {
+		return rows[:n]
+	}
+	return rows
+}
+
+func sortBy[T any](rows []T, less func(a, b T) bool) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		return less(rows[i], rows[j])
+	})
+}
+
+// The lookups below return the index of a row, or -1 if it doesn't exist.
+
+func (d *memData) userIndex(id uuid.UUID) int {
+	for i, u := range d.users {
+		if u.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (d *memData) feedIndex(id int32) int {
+	for i, f := range d.feeds {
+		if f.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (d *memData) postIndex(id int32) int {
+	for i, p := range d.posts {
+		if p.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (d *memData) enclosureIndex(id int32) int {
+	for i, e := range d.postEnclosures {
+		if e.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (d *memData) categoryIndex(id int32) int {
+	for i, c := range d.categories {
+		if c.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (d *memData) subscriptionIndex(id int32) int {
+	for i, s := range d.subscriptions {
+		if s.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// follows reports whether the user follows the feed.
+func (d *memData) follows(userID uuid.UUID, feedID int32) bool {
+	for _, ff := range d.feedFollows {
+		if ff.UserID == userID && ff.FeedID == feedID {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteWhere removes the rows matching match and returns the removed rows.
+func deleteWhere[T any](rows *[]T, match func(T) bool) []T {
+	var kept, removed []T
+	for _, row := range *rows {
+		if match(row) {
+			removed = append(removed, row)
+		} else {
+			kept = append(kept, row)
+		}
+	}
+	*rows = kept
+	return removed
+}
+
+// The deletes below follow the ON DELETE rules of the schema.
+
+// deleteUsers fails if a matching user still owns feeds, as feeds.user_id
+// is ON DELETE RESTRICT.
+func (d *memData) deleteUsers(match func(database.User) bool) error {
+	for _, f := range d.feeds {
+		if i := d.userIndex(f.UserID); i >= 0 && match(d.users[i]) {
+			return foreignKeyViolation("feeds.user_id")
+		}
+	}
+	for _, u := range deleteWhere(&d.users, match) {
+		deleteWhere(&d.sessions, func(s database.Session) bool { return s.UserID == u.ID })
+		deleteWhere(&d.feedFollows, func(ff database.FeedFollow) bool { return ff.UserID == u.ID })
+		deleteWhere(&d.downloads, func(dl database.Download) bool { return dl.UserID == u.ID })
+	}
+	return nil
+}
+
+func (d *memData) deleteFeeds(match func(database.Feed) bool) {
+	for _, f := range deleteWhere(&d.feeds, match) {
+		deleteWhere(&d.feedFollows, func(ff database.FeedFollow) bool { return ff.FeedID == f.ID })
+		deleteWhere(&d.subscriptions, func(s database.WebsubSubscription) bool { return s.FeedID == f.ID })
+		d.deletePosts(func(p database.Post) bool { return p.FeedID == f.ID })
+	}
+}
+
+func (d *memData) deletePosts(match func(database.Post) bool) {
+	for _, p := range deleteWhere(&d.posts, match) {
+		deleteWhere(&d.postCategories, func(pc database.PostCategory) bool { return pc.PostID == p.ID })
+		for _, e := range deleteWhere(&d.postEnclosures, func(e database.PostEnclosure) bool { return e.PostID == p.ID }) {
+			deleteWhere(&d.downloads, func(dl database.Download) bool { return dl.EnclosureID == e.ID })
+		}
+	}
+}
+
+func (d *memData) deleteCategories(match func(database.Category) bool) {
+	for _, c := range deleteWhere(&d.categories, match) {
+		deleteWhere(&d.postCategories, func(pc database.PostCategory) bool { return pc.CategoryID == c.ID })
+	}
+}
@@ -0,0 +1,353 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Shubham-Hazra/blog-aggregator/internal/database"
+	"github.com/google/uuid"
+)
+
+func (q *memQueries) insertFeed(f database.Feed) error {
+	if q.d.userIndex(f.UserID) < 0 {
+		return foreignKeyViolation("feeds.user_id")
+	}
+	for _, existing := range q.d.feeds {
+		if existing.ID == f.ID {
+			return uniqueViolation("feeds.id")
+		}
+		if existing.Url == f.Url {
+			return uniqueViolation("feeds.url")
+		}
+	}
+	q.d.feeds = append(q.d.feeds, f)
+	return nil
+}
+
+// updateFeed applies update to the feed with the given id and reports
+// whether it exists.
+func (q *memQueries) updateFeed(id int32, update func(f *database.Feed)) bool {
+	i := q.d.feedIndex(id)
+	if i < 0 {
+		return false
+	}
+	update(&q.d.feeds[i])
+	return true
+}
+
+func (q *memQueries) insertFeedFollow(ff database.FeedFollow) error {
+	if q.d.userIndex(ff.UserID) < 0 {
+		return foreignKeyViolation("feed_follows.user_id")
+	}
+	if q.d.feedIndex(ff.FeedID) < 0 {
+		return foreignKeyViolation("feed_follows.feed_id")
+	}
+	for _, existing := range q.d.feedFollows {
+		if existing.ID == ff.ID {
+			return uniqueViolation("feed_follows.id")
+		}
+		if existing.UserID == ff.UserID && existing.FeedID == ff.FeedID {
+			return uniqueViolation("feed_follows.user_id, feed_follows.feed_id")
+		}
+	}
+	q.d.feedFollows = append(q.d.feedFollows, ff)
+	return nil
+}
+
+func (q *memQueries) CountFeedsForUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	defer q.lock()()
+	var count int64
+	for _, f := range q.d.feeds {
+		if f.UserID == userID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (q *memQueries) CreateFeed(ctx context.Context, arg database.CreateFeedParams) (database.Feed, error) {
+	defer q.lock()()
+	f := database.Feed{
+		ID:        q.d.nextID("feeds"),
+		Name:      arg.Name,
+		Url:       arg.Url,
+		UserID:    arg.UserID,
+		CreatedAt: arg.CreatedAt,
+		UpdatedAt: arg.UpdatedAt,
+	}
+	if err := q.insertFeed(f); err != nil {
+		return database.Feed{}, err
+	}
+	return f, nil
+}
+
+func (q *memQueries) DeleteFeed(ctx context.Context, id int32) error {
+	defer q.lock()()
+	q.d.deleteFeeds(func(f database.Feed) bool { return f.ID == id })
+	return nil
+}
+
+func (q *memQueries) DeleteFeedsForUser(ctx context.Context, userID uuid.UUID) error {
+	defer q.lock()()
+	q.d.deleteFeeds(func(f database.Feed) bool { return f.UserID == userID })
+	return nil
+}
+
+func (q *memQueries) DisableFeed(ctx context.Context, arg database.DisableFeedParams) error {
+	defer q.lock()()
+	q.updateFeed(arg.ID, func(f *database.Feed) {
+		f.DisabledAt = nullNow()
+		f.DisabledReason = arg.DisabledReason
+		f.UpdatedAt = nullNow()
+	})
+	return nil
+}
+
+func (q *memQueries) GetFeed(ctx context.Context, id int32) (database.Feed, error) {
+	defer q.lock()()
+	if i := q.d.feedIndex(id); i >= 0 {
+		return q.d.feeds[i], nil
+	}
+	return database.Feed{}, sql.ErrNoRows
+}
+
+func (q *memQueries) GetFeedFromUrl(ctx context.Context, url string) (database.Feed, error) {
+	defer q.lock()()
+	for _, f := range q.d.feeds {
+		if f.Url == url {
+			return f, nil
+		}
+	}
+	return database.Feed{}, sql.ErrNoRows
+}
+
+func (q *memQueries) GetFeedStats(ctx context.Context, feedID int32) (database.GetFeedStatsRow, error) {
+	defer q.lock()()
+	var stats database.GetFeedStatsRow
+	for _, ff := range q.d.feedFollows {
+		if ff.FeedID == feedID {
+			stats.FollowerCount++
+		}
+	}
+	for _, p := range q.d.posts {
+		if p.FeedID == feedID {
+			stats.PostCount++
+		}
+	}
+	return stats, nil
+}
+
+func (q *memQueries) GetFeeds(ctx context.Context) ([]database.GetFeedsRow, error) {
+	defer q.lock()()
+	var items []database.GetFeedsRow
+	for _, f := range q.d.feeds {
+		i := q.d.userIndex(f.UserID)
+		if i < 0 {
+			continue
+		}
+		items = append(items, database.GetFeedsRow{
+			FeedName: f.Name,
+			FeedUrl:  f.Url,
+			UserName: q.d.users[i].Name,
+		})
+	}
+	return items, nil
+}
+
+func (q *memQueries) GetNextFeedsToFetch(ctx context.Context, arg database.GetNextFeedsToFetchParams) ([]database.Feed, error) {
+	defer q.lock()()
+	t := now()
+	var items []database.Feed
+	for _, f := range q.d.feeds {
+		if f.DisabledAt.Valid {
+			continue
+		}
+		if f.NextFetchAt.Valid && f.NextFetchAt.Time.After(t) {
+			continue
+		}
+		if f.LastFetchedAt.Valid && f.MinRefreshSeconds.Valid &&
+			f.LastFetchedAt.Time.Add(seconds(f.MinRefreshSeconds.Int32)).After(t) {
+			continue
+		}
+		if f.SkipHours&arg.HourBit != 0 || f.SkipDays&arg.DayBit != 0 {
+			continue
+		}
+		items = append(items, f)
+	}
+	sortBy(items, func(a, b database.Feed) bool {
+		if a.NextFetchAt != b.NextFetchAt {
+			return lessNullsFirst(a.NextFetchAt, b.NextFetchAt)
+		}
+		return lessNullsFirst(a.LastFetchedAt, b.LastFetchedAt)
+	})
+	return limit(items, arg.Limit), nil
+}
+
+func (q *memQueries) MarkFeedFetched(ctx context.Context, id int32) error {
+	defer q.lock()()
+	q.updateFeed(id, func(f *database.Feed) {
+		f.LastFetchedAt = nullNow()
+		f.UpdatedAt = nullNow()
+	})
+	return nil
+}
+
+func (q *memQueries) MoveFeedFollows(ctx context.Context, arg database.MoveFeedFollowsParams) error {
+	defer q.lock()()
+	var moved []database.FeedFollow
+	for _, ff := range q.d.feedFollows {
+		if ff.FeedID == arg.SourceFeedID && !q.d.follows(ff.UserID, arg.TargetFeedID) {
+			moved = append(moved, ff)
+		}
+	}
+	if len(moved) > 0 && q.d.feedIndex(arg.TargetFeedID) < 0 {
+		return foreignKeyViolation("feed_follows.feed_id")
+	}
+	for _, ff := range moved {
+		q.d.feedFollows = append(q.d.feedFollows, database.FeedFollow{
+			ID:        q.d.nextID("feed_follows"),
+			CreatedAt: ff.CreatedAt,
+			UpdatedAt: nullNow(),
+			UserID:    ff.UserID,
+			FeedID:    arg.TargetFeedID,
+		})
+	}
+	return nil
+}
+
+func (q *memQueries) MoveFeedPosts(ctx context.Context, arg database.MoveFeedPostsParams) error {
+	defer q.lock()()
+	if q.d.feedIndex(arg.TargetFeedID) < 0 {
+		for _, p := range q.d.posts {
+			if p.FeedID == arg.SourceFeedID {
+				return foreignKeyViolation("posts.feed_id")
+			}
+		}
+		return nil
+	}
+	for i := range q.d.posts {
+		if q.d.posts[i].FeedID == arg.SourceFeedID {
+			q.d.posts[i].FeedID = arg.TargetFeedID
+			q.d.posts[i].UpdatedAt = now()
+		}
+	}
+	return nil
+}
+
+// NotifyFeedAdded does nothing: there is no other process to notify.
+func (q *memQueries) NotifyFeedAdded(ctx context.Context, feedID string) error {
+	return nil
+}
+
+func (q *memQueries) PostponeFeedFetch(ctx context.Context, arg database.PostponeFeedFetchParams) error {
+	defer q.lock()()
+	q.updateFeed(arg.ID, func(f *database.Feed) {
+		f.NextFetchAt = sql.NullTime{Time: after(arg.DelaySeconds), Valid: true}
+		f.UpdatedAt = nullNow()
+	})
+	return nil
+}
+
+func (q *memQueries) ReassignFeeds(ctx context.Context, arg database.ReassignFeedsParams) error {
+	defer q.lock()()
+	if q.d.userIndex(arg.ToUserID) < 0 {
+		for _, f := range q.d.feeds {
+			if f.UserID == arg.FromUserID {
+				return foreignKeyViolation("feeds.user_id")
+			}
+		}
+		return nil
+	}
+	for i := range q.d.feeds {
+		if q.d.feeds[i].UserID == arg.FromUserID {
+			q.d.feeds[i].UserID = arg.ToUserID
+			q.d.feeds[i].UpdatedAt = nullNow()
+		}
+	}
+	return nil
+}
+
+func (q *memQueries) RenameFeed(ctx context.Context, arg database.RenameFeedParams) (database.Feed, error) {
+	defer q.lock()()
+	if !q.updateFeed(arg.ID, func(f *database.Feed) {
+		f.Name = arg.Name
+		f.UpdatedAt = nullNow()
+	}) {
+		return database.Feed{}, sql.ErrNoRows
+	}
+	return q.d.feeds[q.d.feedIndex(arg.ID)], nil
+}
+
+func (q *memQueries) UpdateFeedRefreshHints(ctx context.Context, arg database.UpdateFeedRefreshHintsParams) error {
+	defer q.lock()()
+	q.updateFeed(arg.ID, func(f *database.Feed) {
+		f.MinRefreshSeconds = arg.MinRefreshSeconds
+		f.SkipHours = arg.SkipHours
+		f.SkipDays = arg.SkipDays
+	})
+	return nil
+}
+
+func (q *memQueries) UpdateFeedUrl(ctx context.Context, arg database.UpdateFeedUrlParams) (database.Feed, error) {
+	defer q.lock()()
+	for _, f := range q.d.feeds {
+		if f.Url == arg.Url && f.ID != arg.ID {
+			return database.Feed{}, uniqueViolation("feeds.url")
+		}
+	}
+	if !q.updateFeed(arg.ID, func(f *database.Feed) {
+		f.Url = arg.Url
+		f.DisabledAt = sql.NullTime{}
+		f.DisabledReason = sql.NullString{}
+		f.UpdatedAt = nullNow()
+	}) {
+		return database.Feed{}, sql.ErrNoRows
+	}
+	return q.d.feeds[q.d.feedIndex(arg.ID)], nil
+}
+
+func (q *memQueries) CreateFeedFollow(ctx context.Context, arg database.CreateFeedFollowParams) (database.CreateFeedFollowRow, error) {
+	defer q.lock()()
+	ff := database.FeedFollow{
+		ID:        q.d.nextID("feed_follows"),
+		CreatedAt: arg.CreatedAt,
+		UpdatedAt: arg.UpdatedAt,
+		UserID:    arg.UserID,
+		FeedID:    arg.FeedID,
+	}
+	if err := q.insertFeedFollow(ff); err != nil {
+		return database.CreateFeedFollowRow{}, err
+	}
+	return database.CreateFeedFollowRow{
+		ID:        ff.ID,
+		CreatedAt: ff.CreatedAt,
+		UpdatedAt: ff.UpdatedAt,
+		UserID:    ff.UserID,
+		FeedID:    ff.FeedID,
+		FeedName:  q.d.feeds[q.d.feedIndex(ff.FeedID)].Name,
+		UserName:  q.d.users[q.d.userIndex(ff.UserID)].Name,
+	}, nil
+}
+
+func (q *memQueries) DeleteFeedFollowsForUser(ctx context.Context, arg database.DeleteFeedFollowsForUserParams) error {
+	defer q.lock()()
+	deleteWhere(&q.d.feedFollows, func(ff database.FeedFollow) bool {
+		i := q.d.feedIndex(ff.FeedID)
+		return ff.UserID == arg.ID && i >= 0 && q.d.feeds[i].Url == arg.Url
+	})
+	return nil
+}
+
+func (q *memQueries) GetFeedFollowsForUser(ctx context.Context, id uuid.UUID) ([]string, error) {
+	defer q.lock()()
+	var items []string
+	for _, ff := range q.d.feedFollows {
+		if ff.UserID != id {
+			continue
+		}
+		if i := q.d.feedIndex(ff.FeedID); i >= 0 {
+			items = append(items, q.d.feeds[i].Name)
+		}
+	}
+	return items, nil
+}
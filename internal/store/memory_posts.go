@@ -0,0 +1,365 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/Shubham-Hazra/blog-aggregator/internal/database"
+	"github.com/google/uuid"
+)
+
+func (q *memQueries) insertPost(p database.Post) error {
+	if q.d.feedIndex(p.FeedID) < 0 {
+		return foreignKeyViolation("posts.feed_id")
+	}
+	for _, existing := range q.d.posts {
+		if existing.ID == p.ID {
+			return uniqueViolation("posts.id")
+		}
+		if existing.Url == p.Url {
+			return uniqueViolation("posts.url")
+		}
+	}
+	q.d.posts = append(q.d.posts, p)
+	return nil
+}
+
+func (q *memQueries) insertCategory(c database.Category) error {
+	for _, existing := range q.d.categories {
+		if existing.ID == c.ID {
+			return uniqueViolation("categories.id")
+		}
+		if existing.Name == c.Name {
+			return uniqueViolation("categories.name")
+		}
+	}
+	q.d.categories = append(q.d.categories, c)
+	return nil
+}
+
+func (q *memQueries) insertPostCategory(pc database.PostCategory) error {
+	if q.d.postIndex(pc.PostID) < 0 {
+		return foreignKeyViolation("post_categories.post_id")
+	}
+	if q.d.categoryIndex(pc.CategoryID) < 0 {
+		return foreignKeyViolation("post_categories.category_id")
+	}
+	for _, existing := range q.d.postCategories {
+		if existing == pc {
+			return uniqueViolation("post_categories.post_id, post_categories.category_id")
+		}
+	}
+	q.d.postCategories = append(q.d.postCategories, pc)
+	return nil
+}
+
+func (q *memQueries) insertPostEnclosure(e database.PostEnclosure) error {
+	if q.d.postIndex(e.PostID) < 0 {
+		return foreignKeyViolation("post_enclosures.post_id")
+	}
+	for _, existing := range q.d.postEnclosures {
+		if existing.ID == e.ID {
+			return uniqueViolation("post_enclosures.id")
+		}
+		if existing.PostID == e.PostID && existing.Url == e.Url {
+			return uniqueViolation("post_enclosures.post_id, post_enclosures.url")
+		}
+	}
+	q.d.postEnclosures = append(q.d.postEnclosures, e)
+	return nil
+}
+
+func (q *memQueries) insertDownload(dl database.Download) error {
+	if q.d.userIndex(dl.UserID) < 0 {
+		return foreignKeyViolation("downloads.user_id")
+	}
+	if q.d.enclosureIndex(dl.EnclosureID) < 0 {
+		return foreignKeyViolation("downloads.enclosure_id")
+	}
+	for _, existing := range q.d.downloads {
+		if existing.ID == dl.ID {
+			return uniqueViolation("downloads.id")
+		}
+		if existing.UserID == dl.UserID && existing.EnclosureID == dl.EnclosureID {
+			return uniqueViolation("downloads.user_id, downloads.enclosure_id")
+		}
+	}
+	q.d.downloads = append(q.d.downloads, dl)
+	return nil
+}
+
+// hasCategory reports whether the post is filed under the named category.
+func (d *memData) hasCategory(postID int32, name string) bool {
+	for _, pc := range d.postCategories {
+		if pc.PostID != postID {
+			continue
+		}
+		if i := d.categoryIndex(pc.CategoryID); i >= 0 && d.categories[i].Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// followedEnclosure is an enclosure of a post in a feed the user follows.
+type followedEnclosure struct {
+	enclosure database.PostEnclosure
+	post      database.Post
+	feed      database.Feed
+}
+
+// followedEnclosures returns the enclosures in feeds the user follows,
+// newest post first.
+func (d *memData) followedEnclosures(userID uuid.UUID) []followedEnclosure {
+	var items []followedEnclosure
+	for _, e := range d.postEnclosures {
+		p := d.posts[d.postIndex(e.PostID)]
+		f := d.feeds[d.feedIndex(p.FeedID)]
+		if d.follows(userID, f.ID) {
+			items = append(items, followedEnclosure{enclosure: e, post: p, feed: f})
+		}
+	}
+	sortBy(items, func(a, b followedEnclosure) bool {
+		return lessDescending(a.post.PublishedAt, b.post.PublishedAt)
+	})
+	return items
+}
+
+func (q *memQueries) CreatePost(ctx context.Context, arg database.CreatePostParams) (database.CreatePostRow, error) {
+	defer q.lock()()
+	t := now()
+	p := database.Post{
+		ID:          q.d.nextID("posts"),
+		CreatedAt:   t,
+		UpdatedAt:   t,
+		Title:       arg.Title,
+		Url:         arg.Url,
+		Description: arg.Description,
+		PublishedAt: arg.PublishedAt,
+		FeedID:      arg.FeedID,
+		Author:      arg.Author,
+	}
+	if err := q.insertPost(p); err != nil {
+		return database.CreatePostRow{}, err
+	}
+	return database.CreatePostRow{ID: p.ID, CreatedAt: p.CreatedAt, UpdatedAt: p.UpdatedAt}, nil
+}
+
+func (q *memQueries) GetPostsForUser(ctx context.Context, arg database.GetPostsForUserParams) ([]database.GetPostsForUserRow, error) {
+	defer q.lock()()
+	var items []database.GetPostsForUserRow
+	for _, p := range q.d.posts {
+		f := q.d.feeds[q.d.feedIndex(p.FeedID)]
+		if !q.d.follows(arg.UserID, f.ID) {
+			continue
+		}
+		if arg.Author.Valid && (!p.Author.Valid ||
+			!strings.Contains(strings.ToLower(p.Author.String), strings.ToLower(arg.Author.String))) {
+			continue
+		}
+		if arg.Category.Valid && !q.d.hasCategory(p.ID, arg.Category.String) {
+			continue
+		}
+		items = append(items, database.GetPostsForUserRow{
+			ID:          p.ID,
+			CreatedAt:   p.CreatedAt,
+			UpdatedAt:   p.UpdatedAt,
+			Title:       p.Title,
+			Url:         p.Url,
+			Description: p.Description,
+			PublishedAt: p.PublishedAt,
+			FeedID:      p.FeedID,
+			Author:      p.Author,
+			FeedName:    f.Name,
+		})
+	}
+	sortBy(items, func(a, b database.GetPostsForUserRow) bool {
+		return lessDescending(a.PublishedAt, b.PublishedAt)
+	})
+	return limit(items, arg.Limit), nil
+}
+
+func (q *memQueries) GetRecentPostTimesForFeed(ctx context.Context, arg database.GetRecentPostTimesForFeedParams) ([]sql.NullTime, error) {
+	defer q.lock()()
+	var items []sql.NullTime
+	for _, p := range q.d.posts {
+		if p.FeedID == arg.FeedID && p.PublishedAt.Valid {
+			items = append(items, p.PublishedAt)
+		}
+	}
+	sortBy(items, lessDescending)
+	return limit(items, arg.Limit), nil
+}
+
+func (q *memQueries) CreatePostCategory(ctx context.Context, arg database.CreatePostCategoryParams) error {
+	defer q.lock()()
+	pc := database.PostCategory{PostID: arg.PostID, CategoryID: arg.CategoryID}
+	for _, existing := range q.d.postCategories {
+		if existing == pc {
+			return nil
+		}
+	}
+	return q.insertPostCategory(pc)
+}
+
+func (q *memQueries) UpsertCategory(ctx context.Context, name string) (database.Category, error) {
+	defer q.lock()()
+	for _, c := range q.d.categories {
+		if c.Name == name {
+			return c, nil
+		}
+	}
+	c := database.Category{ID: q.d.nextID("categories"), Name: name}
+	if err := q.insertCategory(c); err != nil {
+		return database.Category{}, err
+	}
+	return c, nil
+}
+
+func (q *memQueries) CreatePostEnclosure(ctx context.Context, arg database.CreatePostEnclosureParams) error {
+	defer q.lock()()
+	for _, existing := range q.d.postEnclosures {
+		if existing.PostID == arg.PostID && existing.Url == arg.Url {
+			return nil
+		}
+	}
+	return q.insertPostEnclosure(database.PostEnclosure{
+		ID:              q.d.nextID("post_enclosures"),
+		CreatedAt:       now(),
+		PostID:          arg.PostID,
+		Url:             arg.Url,
+		Length:          arg.Length,
+		MimeType:        arg.MimeType,
+		DurationSeconds: arg.DurationSeconds,
+		Episode:         arg.Episode,
+		Season:          arg.Season,
+		ImageUrl:        arg.ImageUrl,
+	})
+}
+
+func (q *memQueries) GetEnclosuresForPostUrl(ctx context.Context, url string) ([]database.GetEnclosuresForPostUrlRow, error) {
+	defer q.lock()()
+	var items []database.GetEnclosuresForPostUrlRow
+	for _, e := range q.d.postEnclosures {
+		p := q.d.posts[q.d.postIndex(e.PostID)]
+		if p.Url != url {
+			continue
+		}
+		items = append(items, database.GetEnclosuresForPostUrlRow{
+			ID:              e.ID,
+			CreatedAt:       e.CreatedAt,
+			PostID:          e.PostID,
+			Url:             e.Url,
+			Length:          e.Length,
+			MimeType:        e.MimeType,
+			DurationSeconds: e.DurationSeconds,
+			Episode:         e.Episode,
+			Season:          e.Season,
+			ImageUrl:        e.ImageUrl,
+			FeedName:        q.d.feeds[q.d.feedIndex(p.FeedID)].Name,
+		})
+	}
+	sortBy(items, func(a, b database.GetEnclosuresForPostUrlRow) bool {
+		return a.ID < b.ID
+	})
+	return items, nil
+}
+
+func (q *memQueries) GetEpisodesForUser(ctx context.Context, arg database.GetEpisodesForUserParams) ([]database.GetEpisodesForUserRow, error) {
+	defer q.lock()()
+	var items []database.GetEpisodesForUserRow
+	for _, fe := range limit(q.d.followedEnclosures(arg.UserID), arg.Limit) {
+		e := fe.enclosure
+		items = append(items, database.GetEpisodesForUserRow{
+			ID:              e.ID,
+			CreatedAt:       e.CreatedAt,
+			PostID:          e.PostID,
+			Url:             e.Url,
+			Length:          e.Length,
+			MimeType:        e.MimeType,
+			DurationSeconds: e.DurationSeconds,
+			Episode:         e.Episode,
+			Season:          e.Season,
+			ImageUrl:        e.ImageUrl,
+			PostTitle:       fe.post.Title,
+			PublishedAt:     fe.post.PublishedAt,
+			FeedName:        fe.feed.Name,
+		})
+	}
+	return items, nil
+}
+
+func (q *memQueries) GetNewEnclosuresForUser(ctx context.Context, arg database.GetNewEnclosuresForUserParams) ([]database.GetNewEnclosuresForUserRow, error) {
+	defer q.lock()()
+	var items []database.GetNewEnclosuresForUserRow
+	for _, fe := range q.d.followedEnclosures(arg.UserID) {
+		e := fe.enclosure
+		if q.d.downloaded(arg.UserID, e.ID) {
+			continue
+		}
+		items = append(items, database.GetNewEnclosuresForUserRow{
+			ID:              e.ID,
+			CreatedAt:       e.CreatedAt,
+			PostID:          e.PostID,
+			Url:             e.Url,
+			Length:          e.Length,
+			MimeType:        e.MimeType,
+			DurationSeconds: e.DurationSeconds,
+			Episode:         e.Episode,
+			Season:          e.Season,
+			ImageUrl:        e.ImageUrl,
+			FeedName:        fe.feed.Name,
+		})
+	}
+	return limit(items, arg.Limit), nil
+}
+
+// downloaded reports whether the user has downloaded the enclosure.
+func (d *memData) downloaded(userID uuid.UUID, enclosureID int32) bool {
+	for _, dl := range d.downloads {
+		if dl.UserID == userID && dl.EnclosureID == enclosureID {
+			return true
+		}
+	}
+	return false
+}
+
+func (q *memQueries) GetDownload(ctx context.Context, arg database.GetDownloadParams) (database.Download, error) {
+	defer q.lock()()
+	for _, dl := range q.d.downloads {
+		if dl.UserID == arg.UserID && dl.EnclosureID == arg.EnclosureID {
+			return dl, nil
+		}
+	}
+	return database.Download{}, sql.ErrNoRows
+}
+
+func (q *memQueries) UpsertDownload(ctx context.Context, arg database.UpsertDownloadParams) (database.Download, error) {
+	defer q.lock()()
+	for i, dl := range q.d.downloads {
+		if dl.UserID == arg.UserID && dl.EnclosureID == arg.EnclosureID {
+			dl.Path = arg.Path
+			dl.Size = arg.Size
+			dl.Sha256 = arg.Sha256
+			dl.UpdatedAt = now()
+			q.d.downloads[i] = dl
+			return dl, nil
+		}
+	}
+	t := now()
+	dl := database.Download{
+		ID:          q.d.nextID("downloads"),
+		CreatedAt:   t,
+		UpdatedAt:   t,
+		UserID:      arg.UserID,
+		EnclosureID: arg.EnclosureID,
+		Path:        arg.Path,
+		Size:        arg.Size,
+		Sha256:      arg.Sha256,
+	}
+	if err := q.insertDownload(dl); err != nil {
+		return database.Download{}, err
+	}
+	return dl, nil
+}
@@ -0,0 +1,174 @@
+package store
+
+import (
+	"context"
+
+	"github.com/Shubham-Hazra/blog-aggregator/internal/database"
+	"github.com/google/uuid"
+)
+
+// ResetTables empties every table. Like TRUNCATE, it leaves the id
+// sequences where they were.
+func (q *memQueries) ResetTables(ctx context.Context) error {
+	defer q.lock()()
+	*q.d = memData{sequences: q.d.sequences}
+	return nil
+}
+
+func (q *memQueries) DeleteAllPosts(ctx context.Context) error {
+	defer q.lock()()
+	q.d.deletePosts(func(database.Post) bool { return true })
+	return nil
+}
+
+func (q *memQueries) DeleteUnusedCategories(ctx context.Context) error {
+	defer q.lock()()
+	q.d.deleteCategories(func(c database.Category) bool {
+		for _, pc := range q.d.postCategories {
+			if pc.CategoryID == c.ID {
+				return false
+			}
+		}
+		return true
+	})
+	return nil
+}
+
+func (q *memQueries) DeleteAllFeedFollowsForUser(ctx context.Context, userID uuid.UUID) error {
+	defer q.lock()()
+	deleteWhere(&q.d.feedFollows, func(ff database.FeedFollow) bool { return ff.UserID == userID })
+	return nil
+}
+
+func (q *memQueries) DeleteDownloadsForUser(ctx context.Context, userID uuid.UUID) error {
+	defer q.lock()()
+	deleteWhere(&q.d.downloads, func(dl database.Download) bool { return dl.UserID == userID })
+	return nil
+}
+
+func (q *memQueries) DatabaseHasData(ctx context.Context) (bool, error) {
+	defer q.lock()()
+	d := q.d
+	return len(d.users) > 0 || len(d.feeds) > 0 || len(d.posts) > 0 || len(d.categories) > 0, nil
+}
+
+func (q *memQueries) ListAllCategories(ctx context.Context) ([]database.Category, error) {
+	defer q.lock()()
+	items := append([]database.Category(nil), q.d.categories...)
+	sortBy(items, func(a, b database.Category) bool { return a.ID < b.ID })
+	return items, nil
+}
+
+func (q *memQueries) ListAllDownloads(ctx context.Context) ([]database.Download, error) {
+	defer q.lock()()
+	items := append([]database.Download(nil), q.d.downloads...)
+	sortBy(items, func(a, b database.Download) bool { return a.ID < b.ID })
+	return items, nil
+}
+
+func (q *memQueries) ListAllFeedFollows(ctx context.Context) ([]database.FeedFollow, error) {
+	defer q.lock()()
+	items := append([]database.FeedFollow(nil), q.d.feedFollows...)
+	sortBy(items, func(a, b database.FeedFollow) bool { return a.ID < b.ID })
+	return items, nil
+}
+
+func (q *memQueries) ListAllFeeds(ctx context.Context) ([]database.Feed, error) {
+	defer q.lock()()
+	items := append([]database.Feed(nil), q.d.feeds...)
+	sortBy(items, func(a, b database.Feed) bool { return a.ID < b.ID })
+	return items, nil
+}
+
+func (q *memQueries) ListAllPostCategories(ctx context.Context) ([]database.PostCategory, error) {
+	defer q.lock()()
+	items := append([]database.PostCategory(nil), q.d.postCategories...)
+	sortBy(items, func(a, b database.PostCategory) bool {
+		if a.PostID != b.PostID {
+			return a.PostID < b.PostID
+		}
+		return a.CategoryID < b.CategoryID
+	})
+	return items, nil
+}
+
+func (q *memQueries) ListAllPostEnclosures(ctx context.Context) ([]database.PostEnclosure, error) {
+	defer q.lock()()
+	items := append([]database.PostEnclosure(nil), q.d.postEnclosures...)
+	sortBy(items, func(a, b database.PostEnclosure) bool { return a.ID < b.ID })
+	return items, nil
+}
+
+func (q *memQueries) ListAllPosts(ctx context.Context) ([]database.Post, error) {
+	defer q.lock()()
+	items := append([]database.Post(nil), q.d.posts...)
+	sortBy(items, func(a, b database.Post) bool { return a.ID < b.ID })
+	return items, nil
+}
+
+func (q *memQueries) RestoreCategory(ctx context.Context, arg database.RestoreCategoryParams) error {
+	defer q.lock()()
+	return q.insertCategory(database.Category(arg))
+}
+
+func (q *memQueries) RestoreDownload(ctx context.Context, arg database.RestoreDownloadParams) error {
+	defer q.lock()()
+	return q.insertDownload(database.Download(arg))
+}
+
+func (q *memQueries) RestoreFeed(ctx context.Context, arg database.RestoreFeedParams) error {
+	defer q.lock()()
+	return q.insertFeed(database.Feed(arg))
+}
+
+func (q *memQueries) RestoreFeedFollow(ctx context.Context, arg database.RestoreFeedFollowParams) error {
+	defer q.lock()()
+	return q.insertFeedFollow(database.FeedFollow(arg))
+}
+
+func (q *memQueries) RestorePost(ctx context.Context, arg database.RestorePostParams) error {
+	defer q.lock()()
+	return q.insertPost(database.Post(arg))
+}
+
+func (q *memQueries) RestorePostCategory(ctx context.Context, arg database.RestorePostCategoryParams) error {
+	defer q.lock()()
+	return q.insertPostCategory(database.PostCategory(arg))
+}
+
+func (q *memQueries) RestorePostEnclosure(ctx context.Context, arg database.RestorePostEnclosureParams) error {
+	defer q.lock()()
+	return q.insertPostEnclosure(database.PostEnclosure(arg))
+}
+
+func (q *memQueries) RestoreUser(ctx context.Context, arg database.RestoreUserParams) error {
+	defer q.lock()()
+	return q.insertUser(database.User(arg))
+}
+
+// SyncSequences moves each id sequence past the largest id in use, so
+// rows created after a restore don't collide with restored ones.
+func (q *memQueries) SyncSequences(ctx context.Context) error {
+	defer q.lock()()
+	d := q.d
+	if d.sequences == nil {
+		d.sequences = make(map[string]int32)
+	}
+	d.sequences["feeds"] = maxID(d.feeds, func(f database.Feed) int32 { return f.ID })
+	d.sequences["feed_follows"] = maxID(d.feedFollows, func(ff database.FeedFollow) int32 { return ff.ID })
+	d.sequences["posts"] = maxID(d.posts, func(p database.Post) int32 { return p.ID })
+	d.sequences["categories"] = maxID(d.categories, func(c database.Category) int32 { return c.ID })
+	d.sequences["post_enclosures"] = maxID(d.postEnclosures, func(e database.PostEnclosure) int32 { return e.ID })
+	d.sequences["downloads"] = maxID(d.downloads, func(dl database.Download) int32 { return dl.ID })
+	return nil
+}
+
+func maxID[T any](rows []T, id func(T) int32) int32 {
+	var max int32
+	for _, row := range rows {
+		if id(row) > max {
+			max = id(row)
+		}
+	}
+	return max
+}
@@ -0,0 +1,195 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Shubham-Hazra/blog-aggregator/internal/database"
+	"github.com/google/uuid"
+)
+
+func (q *memQueries) insertUser(u database.User) error {
+	if u.Role != "admin" && u.Role != "member" {
+		return checkViolation("users.role")
+	}
+	for _, existing := range q.d.users {
+		if existing.ID == u.ID {
+			return uniqueViolation("users.id")
+		}
+		if existing.Name == u.Name {
+			return uniqueViolation("users.name")
+		}
+	}
+	q.d.users = append(q.d.users, u)
+	return nil
+}
+
+// updateUser applies update to the user with the given id and reports
+// whether it exists.
+func (q *memQueries) updateUser(id uuid.UUID, update func(u *database.User)) bool {
+	i := q.d.userIndex(id)
+	if i < 0 {
+		return false
+	}
+	update(&q.d.users[i])
+	q.d.users[i].UpdatedAt = nullNow()
+	return true
+}
+
+func (q *memQueries) CountAdmins(ctx context.Context) (int64, error) {
+	defer q.lock()()
+	var count int64
+	for _, u := range q.d.users {
+		if u.Role == "admin" && !u.DeactivatedAt.Valid {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (q *memQueries) CreateUser(ctx context.Context, arg database.CreateUserParams) (database.User, error) {
+	defer q.lock()()
+	u := database.User{
+		ID:           arg.ID,
+		CreatedAt:    arg.CreatedAt,
+		UpdatedAt:    arg.UpdatedAt,
+		Name:         arg.Name,
+		PasswordHash: arg.PasswordHash,
+		Role:         "member",
+	}
+	if len(q.d.users) == 0 {
+		u.Role = "admin"
+	}
+	if err := q.insertUser(u); err != nil {
+		return database.User{}, err
+	}
+	return u, nil
+}
+
+func (q *memQueries) DeactivateUser(ctx context.Context, id uuid.UUID) error {
+	defer q.lock()()
+	q.updateUser(id, func(u *database.User) {
+		u.DeactivatedAt = nullNow()
+	})
+	return nil
+}
+
+func (q *memQueries) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	defer q.lock()()
+	return q.d.deleteUsers(func(u database.User) bool { return u.ID == id })
+}
+
+func (q *memQueries) GetUser(ctx context.Context, name string) (database.User, error) {
+	defer q.lock()()
+	for _, u := range q.d.users {
+		if u.Name == name {
+			return u, nil
+		}
+	}
+	return database.User{}, sql.ErrNoRows
+}
+
+func (q *memQueries) GetUsers(ctx context.Context) ([]database.User, error) {
+	defer q.lock()()
+	return append([]database.User(nil), q.d.users...), nil
+}
+
+func (q *memQueries) ReactivateUser(ctx context.Context, id uuid.UUID) error {
+	defer q.lock()()
+	q.updateUser(id, func(u *database.User) {
+		u.DeactivatedAt = sql.NullTime{}
+	})
+	return nil
+}
+
+func (q *memQueries) RenameUser(ctx context.Context, arg database.RenameUserParams) (database.User, error) {
+	defer q.lock()()
+	for _, u := range q.d.users {
+		if u.Name == arg.Name && u.ID != arg.ID {
+			return database.User{}, uniqueViolation("users.name")
+		}
+	}
+	if !q.updateUser(arg.ID, func(u *database.User) { u.Name = arg.Name }) {
+		return database.User{}, sql.ErrNoRows
+	}
+	return q.d.users[q.d.userIndex(arg.ID)], nil
+}
+
+func (q *memQueries) SetUserPassword(ctx context.Context, arg database.SetUserPasswordParams) error {
+	defer q.lock()()
+	q.updateUser(arg.ID, func(u *database.User) {
+		u.PasswordHash = arg.PasswordHash
+	})
+	return nil
+}
+
+func (q *memQueries) SetUserRole(ctx context.Context, arg database.SetUserRoleParams) error {
+	defer q.lock()()
+	if arg.Role != "admin" && arg.Role != "member" {
+		return checkViolation("users.role")
+	}
+	q.updateUser(arg.ID, func(u *database.User) {
+		u.Role = arg.Role
+	})
+	return nil
+}
+
+func (q *memQueries) CreateSession(ctx context.Context, arg database.CreateSessionParams) error {
+	defer q.lock()()
+	if q.d.userIndex(arg.UserID) < 0 {
+		return foreignKeyViolation("sessions.user_id")
+	}
+	for _, s := range q.d.sessions {
+		if s.TokenHash == arg.TokenHash {
+			return uniqueViolation("sessions.token_hash")
+		}
+	}
+	q.d.sessions = append(q.d.sessions, database.Session{
+		TokenHash: arg.TokenHash,
+		CreatedAt: now(),
+		ExpiresAt: after(arg.LifetimeSeconds),
+		UserID:    arg.UserID,
+	})
+	return nil
+}
+
+func (q *memQueries) DeleteExpiredSessions(ctx context.Context) error {
+	defer q.lock()()
+	t := now()
+	deleteWhere(&q.d.sessions, func(s database.Session) bool { return !s.ExpiresAt.After(t) })
+	return nil
+}
+
+func (q *memQueries) DeleteOtherSessionsForUser(ctx context.Context, arg database.DeleteOtherSessionsForUserParams) error {
+	defer q.lock()()
+	deleteWhere(&q.d.sessions, func(s database.Session) bool {
+		return s.UserID == arg.UserID && s.TokenHash != arg.TokenHash
+	})
+	return nil
+}
+
+func (q *memQueries) DeleteSession(ctx context.Context, tokenHash string) error {
+	defer q.lock()()
+	deleteWhere(&q.d.sessions, func(s database.Session) bool { return s.TokenHash == tokenHash })
+	return nil
+}
+
+func (q *memQueries) DeleteSessionsForUser(ctx context.Context, userID uuid.UUID) error {
+	defer q.lock()()
+	deleteWhere(&q.d.sessions, func(s database.Session) bool { return s.UserID == userID })
+	return nil
+}
+
+func (q *memQueries) GetUserForSession(ctx context.Context, tokenHash string) (database.User, error) {
+	defer q.lock()()
+	t := now()
+	for _, s := range q.d.sessions {
+		if s.TokenHash != tokenHash || !s.ExpiresAt.After(t) {
+			continue
+		}
+		if i := q.d.userIndex(s.UserID); i >= 0 {
+			return q.d.users[i], nil
+		}
+	}
+	return database.User{}, sql.ErrNoRows
+}
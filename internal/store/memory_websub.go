@@ -0,0 +1,103 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Shubham-Hazra/blog-aggregator/internal/database"
+)
+
+// updateSubscription applies update to the subscription with the given id.
+func (q *memQueries) updateSubscription(id int32, update func(s *database.WebsubSubscription)) {
+	if i := q.d.subscriptionIndex(id); i >= 0 {
+		update(&q.d.subscriptions[i])
+		q.d.subscriptions[i].UpdatedAt = now()
+	}
+}
+
+func (q *memQueries) ActivateWebSubSubscription(ctx context.Context, arg database.ActivateWebSubSubscriptionParams) error {
+	defer q.lock()()
+	q.updateSubscription(arg.ID, func(s *database.WebsubSubscription) {
+		s.State = "active"
+		s.LeaseExpiresAt = sql.NullTime{Time: after(arg.LeaseSeconds), Valid: true}
+	})
+	return nil
+}
+
+func (q *memQueries) GetWebSubSubscription(ctx context.Context, id int32) (database.WebsubSubscription, error) {
+	defer q.lock()()
+	if i := q.d.subscriptionIndex(id); i >= 0 {
+		return q.d.subscriptions[i], nil
+	}
+	return database.WebsubSubscription{}, sql.ErrNoRows
+}
+
+func (q *memQueries) GetWebSubSubscriptionForFeed(ctx context.Context, feedID int32) (database.WebsubSubscription, error) {
+	defer q.lock()()
+	for _, s := range q.d.subscriptions {
+		if s.FeedID == feedID {
+			return s, nil
+		}
+	}
+	return database.WebsubSubscription{}, sql.ErrNoRows
+}
+
+func (q *memQueries) GetWebSubSubscriptionsToRenew(ctx context.Context, arg database.GetWebSubSubscriptionsToRenewParams) ([]database.WebsubSubscription, error) {
+	defer q.lock()()
+	t := now()
+	retryBefore := t.Add(-seconds(arg.RetrySeconds))
+	renewBefore := t.Add(seconds(arg.RenewSeconds))
+	var items []database.WebsubSubscription
+	for _, s := range q.d.subscriptions {
+		switch {
+		case s.State == "new",
+			s.State == "pending" && !s.UpdatedAt.After(retryBefore),
+			s.State == "active" && s.LeaseExpiresAt.Valid && !s.LeaseExpiresAt.Time.After(renewBefore):
+			items = append(items, s)
+		}
+	}
+	sortBy(items, func(a, b database.WebsubSubscription) bool {
+		return a.UpdatedAt.Before(b.UpdatedAt)
+	})
+	return items, nil
+}
+
+func (q *memQueries) SetWebSubSubscriptionState(ctx context.Context, arg database.SetWebSubSubscriptionStateParams) error {
+	defer q.lock()()
+	q.updateSubscription(arg.ID, func(s *database.WebsubSubscription) {
+		s.State = arg.State
+	})
+	return nil
+}
+
+func (q *memQueries) UpsertWebSubSubscription(ctx context.Context, arg database.UpsertWebSubSubscriptionParams) error {
+	defer q.lock()()
+	for _, s := range q.d.subscriptions {
+		if s.FeedID != arg.FeedID {
+			continue
+		}
+		if s.HubUrl != arg.HubUrl || s.TopicUrl != arg.TopicUrl {
+			q.updateSubscription(s.ID, func(s *database.WebsubSubscription) {
+				s.HubUrl = arg.HubUrl
+				s.TopicUrl = arg.TopicUrl
+				s.State = "new"
+			})
+		}
+		return nil
+	}
+	if q.d.feedIndex(arg.FeedID) < 0 {
+		return foreignKeyViolation("websub_subscriptions.feed_id")
+	}
+	t := now()
+	q.d.subscriptions = append(q.d.subscriptions, database.WebsubSubscription{
+		ID:        q.d.nextID("websub_subscriptions"),
+		CreatedAt: t,
+		UpdatedAt: t,
+		FeedID:    arg.FeedID,
+		HubUrl:    arg.HubUrl,
+		TopicUrl:  arg.TopicUrl,
+		Secret:    arg.Secret,
+		State:     "new",
+	})
+	return nil
+}
@@ -0,0 +1,20 @@
+package store
+
+import (
+	"database/sql"
+
+	"github.com/Shubham-Hazra/blog-aggregator/internal/database"
+	"github.com/Shubham-Hazra/blog-aggregator/internal/migrate"
+	"github.com/Shubham-Hazra/blog-aggregator/sql/schema"
+	_ "github.com/lib/pq"
+)
+
+func openPostgres(dbURL string) (Store, error) {
+	db, err := sql.Open("postgres", dbURL)
+	if err != nil {
+		return nil, err
+	}
+	return newSQLStore(db, "postgres", migrate.Postgres, schema.FS, func(db database.DBTX) database.DBTX {
+		return db
+	}), nil
+}
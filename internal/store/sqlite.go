@@ -0,0 +1,154 @@
+package store
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Shubham-Hazra/blog-aggregator/internal/database"
+	"github.com/Shubham-Hazra/blog-aggregator/internal/migrate"
+	"github.com/Shubham-Hazra/blog-aggregator/sql/sqlite"
+	_ "modernc.org/sqlite"
+)
+
+// sqlitePragmas are applied to every connection. Foreign keys are off by
+// default in SQLite, and the aggregator writes from several goroutines.
+// Transactions take the write lock when they begin: busy_timeout doesn't
+// apply when a deferred transaction upgrades from reading to writing, so
+// those fail with SQLITE_BUSY as soon as another connection writes.
+const sqlitePragmas = "_pragma=foreign_keys(1)&_pragma=busy_timeout(10000)&_pragma=journal_mode(WAL)&_txlock=immediate"
+
+// sqliteTimeLayout matches what CURRENT_TIMESTAMP produces, so times
+// written from Go compare correctly with times computed in SQL.
+const sqliteTimeLayout = "2006-01-02 15:04:05.999999"
+
+var (
+	sqliteOverrides   = parseQueries(sqlite.Queries)
+	postgresParameter = regexp.MustCompile(`\$(\d+)`)
+	postgresCast      = regexp.MustCompile(`::\w+`)
+)
+
+// openSQLite opens the database file at path, which may be given as
+// sqlite:gator.db, sqlite:///abs/path/gator.db or sqlite:~/gator.db.
+func openSQLite(path string) (Store, error) {
+	path = strings.TrimPrefix(path, "//")
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(home, rest)
+	}
+
+	dsn := "file:" + path
+	if strings.Contains(path, "?") {
+		dsn += "&" + sqlitePragmas
+	} else {
+		dsn += "?" + sqlitePragmas
+	}
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+	return newSQLStore(db, "sqlite", migrate.SQLite, sqlite.Schema, func(db database.DBTX) database.DBTX {
+		return sqliteDB{db}
+	}), nil
+}
+
+// sqliteDB runs the Postgres queries generated by sqlc against SQLite.
+// Queries with Postgres-only syntax are swapped for the versions in
+// sql/sqlite/queries.sql, the rest only need their placeholders and
+// casts rewritten.
+type sqliteDB struct {
+	db database.DBTX
+}
+
+func (d sqliteDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return d.db.ExecContext(ctx, sqliteQuery(query), sqliteArgs(args)...)
+}
+
+func (d sqliteDB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return d.db.PrepareContext(ctx, sqliteQuery(query))
+}
+
+func (d sqliteDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return d.db.QueryContext(ctx, sqliteQuery(query), sqliteArgs(args)...)
+}
+
+func (d sqliteDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return d.db.QueryRowContext(ctx, sqliteQuery(query), sqliteArgs(args)...)
+}
+
+func sqliteQuery(query string) string {
+	if override, ok := sqliteOverrides[queryName(query)]; ok {
+		return override
+	}
+	query = postgresParameter.ReplaceAllString(query, "?$1")
+	return postgresCast.ReplaceAllString(query, "")
+}
+
+// sqliteArgs stores times as UTC text in the same layout SQLite uses.
+func sqliteArgs(args []interface{}) []interface{} {
+	converted := make([]interface{}, len(args))
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case time.Time:
+			converted[i] = v.UTC().Format(sqliteTimeLayout)
+		case sql.NullTime:
+			if v.Valid {
+				converted[i] = v.Time.UTC().Format(sqliteTimeLayout)
+			} else {
+				converted[i] = nil
+			}
+		default:
+			converted[i] = arg
+		}
+	}
+	return converted
+}
+
+// queryName returns the name sqlc puts in the "-- name: X :kind" line
+// at the start of every generated query.
+func queryName(query string) string {
+	rest, ok := strings.CutPrefix(query, "-- name: ")
+	if !ok {
+		return ""
+	}
+	name, _, _ := strings.Cut(rest, " ")
+	return name
+}
+
+// parseQueries splits a file of sqlc-style queries by name. Each query
+// keeps its "-- name:" line, like the generated Postgres ones do.
+func parseQueries(source string) map[string]string {
+	queries := make(map[string]string)
+	var name string
+	var query strings.Builder
+	flush := func() {
+		if name != "" {
+			queries[name] = strings.TrimSpace(query.String())
+		}
+		query.Reset()
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(source))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "-- name: ") {
+			flush()
+			name = queryName(line)
+		}
+		if name != "" {
+			query.WriteString(line)
+			query.WriteByte('\n')
+		}
+	}
+	flush()
+	return queries
+}
@@ -0,0 +1,173 @@
+package store
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Shubham-Hazra/blog-aggregator/internal/database"
+	"github.com/google/uuid"
+)
+
+var sqliteParameter = regexp.MustCompile(`\?(\d+)`)
+
+func openTestSQLite(t *testing.T) *sqlStore {
+	t.Helper()
+	s, err := openSQLite(filepath.Join(t.TempDir(), "gator.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s.(*sqlStore)
+}
+
+// generatedQueries returns the SQL of every query sqlc generated in
+// internal/database, by name.
+func generatedQueries(t *testing.T) map[string]string {
+	t.Helper()
+	files, err := filepath.Glob("../database/*.sql.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	queries := make(map[string]string)
+	fset := token.NewFileSet()
+	for _, file := range files {
+		f, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			lit, ok := n.(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				return true
+			}
+			query, err := strconv.Unquote(lit.Value)
+			if err == nil && strings.HasPrefix(query, "-- name: ") {
+				queries[queryName(query)] = query
+			}
+			return true
+		})
+	}
+	return queries
+}
+
+// nullArgs returns a NULL for every ?N placeholder in query.
+func nullArgs(query string) []interface{} {
+	count := 0
+	for _, match := range sqliteParameter.FindAllStringSubmatch(query, -1) {
+		n, _ := strconv.Atoi(match[1])
+		count = max(count, n)
+	}
+	return make([]interface{}, count)
+}
+
+func TestSQLiteCompilesEveryQuery(t *testing.T) {
+	s := openTestSQLite(t)
+	ctx := context.Background()
+	migrator, err := s.Migrator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := migrator.Up(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	queries := generatedQueries(t)
+	querier := reflect.TypeOf((*database.Querier)(nil)).Elem()
+	for i := range querier.NumMethod() {
+		name := querier.Method(i).Name
+		t.Run(name, func(t *testing.T) {
+			query, ok := queries[name]
+			if !ok {
+				t.Fatalf("no generated query for %s", name)
+			}
+			// modernc.org/sqlite prepares lazily, but EXPLAIN has SQLite
+			// compile a statement without running it.
+			for _, stmt := range strings.Split(sqliteQuery(query), ";\n") {
+				if strings.TrimSpace(stmt) == "" {
+					continue
+				}
+				rows, err := s.db.QueryContext(ctx, "EXPLAIN "+stmt, nullArgs(stmt)...)
+				if err != nil {
+					t.Fatalf("compiling %s: %v\n%s", name, err, stmt)
+				}
+				rows.Close()
+			}
+		})
+	}
+
+	for name := range sqliteOverrides {
+		if _, ok := queries[name]; !ok {
+			t.Errorf("sql/sqlite/queries.sql overrides %s, which sqlc doesn't generate", name)
+		}
+	}
+}
+
+func TestSQLiteMigrationsRoundTrip(t *testing.T) {
+	s := openTestSQLite(t)
+	ctx := context.Background()
+	migrator, err := s.Migrator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range 2 {
+		if err := migrator.Up(ctx, nil); err != nil {
+			t.Fatal(err)
+		}
+		if err := migrator.Check(ctx); err != nil {
+			t.Fatal(err)
+		}
+		for version := migrator.Latest(); version > 0; version-- {
+			if _, err := migrator.Down(ctx); err != nil {
+				t.Fatalf("rolling back version %d: %v", version, err)
+			}
+		}
+		if version, err := migrator.Version(ctx); err != nil || version != 0 {
+			t.Fatalf("after rolling everything back: version %d, %v", version, err)
+		}
+	}
+}
+
+func TestSQLiteRestrictsDeletingFeedOwners(t *testing.T) {
+	s := openTestSQLite(t)
+	ctx := context.Background()
+	migrator, err := s.Migrator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := migrator.Up(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := s.CreateUser(ctx, database.CreateUserParams{ID: uuid.New(), Name: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	feed, err := s.CreateFeed(ctx, database.CreateFeedParams{Name: "Feed", Url: "https://example.com/feed", UserID: user.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Like ON DELETE RESTRICT on Postgres.
+	if err := s.DeleteUser(ctx, user.ID); err == nil {
+		t.Fatal("DeleteUser succeeded for a user who owns feeds")
+	}
+	if _, err := s.GetFeed(ctx, feed.ID); err != nil {
+		t.Fatalf("feed is gone after a refused DeleteUser: %v", err)
+	}
+
+	if err := s.DeleteFeed(ctx, feed.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DeleteUser(ctx, user.ID); err != nil {
+		t.Errorf("DeleteUser without feeds: %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+// Package store opens the database gator keeps its state in. Postgres
+// and SQLite are supported, picked by the scheme of db_url, and an
+// in-memory store stands in for either in tests.
+package store
+
+import (
+	"context"
+	"database/sql"
+	"io/fs"
+	"strings"
+
+	"github.com/Shubham-Hazra/blog-aggregator/internal/database"
+	"github.com/Shubham-Hazra/blog-aggregator/internal/migrate"
+)
+
+// Store is everything the handlers need from a storage backend: the
+// sqlc queries plus transactions and schema management.
+type Store interface {
+	database.Querier
+
+	// WithTx runs fn with queries bound to a single transaction,
+	// committing it if fn succeeds and rolling it back otherwise.
+	WithTx(ctx context.Context, fn func(q database.Querier) error) error
+	// WithSnapshot runs fn with queries bound to a read-only transaction
+	// that sees the database as it was when the transaction started.
+	WithSnapshot(ctx context.Context, fn func(q database.Querier) error) error
+	// Migrator returns the schema migrations for this backend.
+	Migrator() (*migrate.Migrator, error)
+	// Driver names the backend, e.g. "postgres" or "sqlite".
+	Driver() string
+	Close() error
+}
+
+// Open connects to the database at dbURL. URLs starting with sqlite:
+// open a SQLite file, anything else is handed to the Postgres driver.
+func Open(dbURL string) (Store, error) {
+	if path, ok := strings.CutPrefix(dbURL, "sqlite:"); ok {
+		return openSQLite(path)
+	}
+	return openPostgres(dbURL)
+}
+
+// sqlStore implements Store on top of database/sql. wrap adapts the
+// connection, or a transaction on it, before the queries use it.
+type sqlStore struct {
+	*database.Queries
+	db      *sql.DB
+	driver  string
+	dialect migrate.Dialect
+	schema  fs.FS
+	wrap    func(database.DBTX) database.DBTX
+}
+
+func newSQLStore(db *sql.DB, driver string, dialect migrate.Dialect, schema fs.FS, wrap func(database.DBTX) database.DBTX) *sqlStore {
+	return &sqlStore{
+		Queries: database.New(wrap(db)),
+		db:      db,
+		driver:  driver,
+		dialect: dialect,
+		schema:  schema,
+		wrap:    wrap,
+	}
+}
+
+func (s *sqlStore) WithTx(ctx context.Context, fn func(q database.Querier) error) error {
+	return s.inTx(ctx, nil, fn)
+}
+
+func (s *sqlStore) WithSnapshot(ctx context.Context, fn func(q database.Querier) error) error {
+	return s.inTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}, fn)
+}
+
+func (s *sqlStore) inTx(ctx context.Context, opts *sql.TxOptions, fn func(q database.Querier) error) error {
+	tx, err := s.db.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(database.New(s.wrap(tx))); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *sqlStore) Migrator() (*migrate.Migrator, error) {
+	return migrate.New(s.db, s.dialect, s.schema)
+}
+
+func (s *sqlStore) Driver() string {
+	return s.driver
+}
+
+func (s *sqlStore) Close() error {
+	return s.db.Close()
+}
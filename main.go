@@ -1,46 +1,71 @@
 package main
 
 import (
-	"database/sql"
-	"fmt"
+	"flag"
 	"log"
 	"os"
 
 	"github.com/Shubham-Hazra/blog-aggregator/internal/config"
-	"github.com/Shubham-Hazra/blog-aggregator/internal/database"
 	"github.com/Shubham-Hazra/blog-aggregator/internal/handler"
+	"github.com/Shubham-Hazra/blog-aggregator/internal/store"
 	"github.com/Shubham-Hazra/blog-aggregator/pkg/types"
-	_ "github.com/lib/pq"
 )
 
 func main() {
-    config := &config.Config{}
-    config.Read()
+    flags := flag.NewFlagSet("gator", flag.ContinueOnError)
+    configPath := flags.String("config", "", "path to the config file")
+    flags.Usage = func() { handler.PrintHelp(os.Stderr) }
+    if err := flags.Parse(os.Args[1:]); err != nil {
+        os.Exit(2)
+    }
+    args := flags.Args()
+    if len(args) < 1 {
+        log.Fatal("too few arguments, run \"gator help\" for a list of commands")
+    }
+
+    // help has to work before gator is configured.
+    if args[0] == "help" {
+        handler.PrintHelp(os.Stdout)
+        return
+    }
+
+    config, err := config.Read(*configPath)
+    if err != nil {
+        log.Fatal(err)
+    }
+    if config.DB_URL == "" {
+        log.Fatalf("no database configured, set db_url in %s or GATOR_DB_URL", config.Path())
+    }
     
-    db, err := sql.Open("postgres", config.DB_URL)
+    dbQueries, err := store.Open(config.DB_URL)
     if err != nil {
         log.Fatal(err)
     }
-    dbQueries := database.New(db)
+    defer dbQueries.Close()
     
-    state := handler.NewState(config, dbQueries)
+    state, err := handler.NewState(config, dbQueries)
+    if err != nil {
+        log.Fatal(err)
+    }
     cmdHandler := handler.NewHandler(state)
 
-    if err := executeCommand(cmdHandler); err != nil {
+    // migrate is the one command that has to work on an outdated schema.
+    if args[0] != "migrate" {
+        if err := handler.CheckSchema(state); err != nil {
+            log.Fatal(err)
+        }
+    }
+
+    if err := executeCommand(cmdHandler, args); err != nil {
         log.Fatal(err)
     }
 }
 
-func executeCommand(ch *handler.Handler) error {
-    args := os.Args
-    if len(args) < 2 {
-        return fmt.Errorf("too few arguments")
-    }
-    
+func executeCommand(ch *handler.Handler, args []string) error {
     cmd := types.Command{
-        Name: args[1],
-        Args: args[2:],
+        Name: args[0],
+        Args: args[1:],
     }
     
     return ch.Execute(cmd)
-}
\ No newline at end of file
+}
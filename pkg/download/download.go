@@ -0,0 +1,271 @@
+package download
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PartialSuffix is appended to files that are still being downloaded.
+const PartialSuffix = ".part"
+
+// Result describes a completed download.
+type Result struct {
+	Path   string
+	Size   int64
+	SHA256 string
+}
+
+// errStalled is the cause of a download cancelled for receiving no data.
+var errStalled = errors.New("no data received")
+
+// Downloader fetches media files to disk, resuming interrupted transfers.
+type Downloader struct {
+	Client    *http.Client
+	UserAgent string
+	// ReadTimeout aborts a transfer that receives no data for this long.
+	// There is no limit on the whole transfer, large files take a while.
+	ReadTimeout time.Duration
+}
+
+func New() *Downloader {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 30 * time.Second,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       90 * time.Second,
+	}
+	return &Downloader{
+		Client:      &http.Client{Transport: transport},
+		UserAgent:   "gator",
+		ReadTimeout: time.Minute,
+	}
+}
+
+// Download fetches url into dest. Data is written to dest+PartialSuffix
+// first and resumed with a Range request if that file already exists.
+// The download is complete once everything the server announced in
+// Content-Length or Content-Range has arrived. The length publishers give
+// for enclosures is too often wrong to be checked against.
+func (d *Downloader) Download(ctx context.Context, url, dest string) (*Result, error) {
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return nil, err
+	}
+
+	partPath := dest + PartialSuffix
+	offset := int64(0)
+	if info, err := os.Stat(partPath); err == nil {
+		offset = info.Size()
+	}
+
+	if err := d.fetch(ctx, url, partPath, offset); err != nil {
+		return nil, err
+	}
+
+	checksum, size, err := FileChecksum(partPath)
+	if err != nil {
+		return nil, err
+	}
+	if size == 0 {
+		return nil, fmt.Errorf("empty response for %s", url)
+	}
+
+	if err := os.Rename(partPath, dest); err != nil {
+		return nil, err
+	}
+
+	return &Result{Path: dest, Size: size, SHA256: checksum}, nil
+}
+
+// fetch writes the body of url into partPath, starting at offset. A
+// response that doesn't continue the partial file makes it start over.
+func (d *Downloader) fetch(ctx context.Context, url, partPath string, offset int64) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	if d.UserAgent != "" {
+		req.Header.Set("User-Agent", d.UserAgent)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-")
+	}
+
+	res, err := d.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	restart := func() error {
+		res.Body.Close()
+		if err := os.Truncate(partPath, 0); err != nil {
+			return err
+		}
+		return d.fetch(ctx, url, partPath, 0)
+	}
+
+	// expected is the size of the finished file, or -1 if the server
+	// didn't say.
+	flags := os.O_CREATE | os.O_WRONLY
+	expected := int64(-1)
+	switch res.StatusCode {
+	case http.StatusPartialContent:
+		start, total, ok := parseContentRange(res.Header.Get("Content-Range"))
+		if !ok || start != offset {
+			if offset == 0 {
+				return fmt.Errorf("unexpected Content-Range downloading %s: %q", url, res.Header.Get("Content-Range"))
+			}
+			// Appending this would corrupt the file, so start over.
+			return restart()
+		}
+		flags |= os.O_APPEND
+		switch {
+		case total >= 0:
+			expected = total
+		case res.ContentLength >= 0:
+			expected = offset + res.ContentLength
+		}
+	case http.StatusOK:
+		// The server ignored the Range header, so start over.
+		flags |= os.O_TRUNC
+		offset = 0
+		expected = res.ContentLength
+	case http.StatusRequestedRangeNotSatisfiable:
+		// Only a partial file exactly as long as the resource is done.
+		// Anything else is left over from a different version of it.
+		if offset > 0 {
+			if _, total, ok := parseContentRange(res.Header.Get("Content-Range")); ok && total == offset {
+				return nil
+			}
+			return restart()
+		}
+		return fmt.Errorf("unexpected status downloading %s: %s", url, res.Status)
+	default:
+		return fmt.Errorf("unexpected status downloading %s: %s", url, res.Status)
+	}
+
+	file, err := os.OpenFile(partPath, flags, 0o644)
+	if err != nil {
+		return err
+	}
+
+	var body io.Reader = res.Body
+	if d.ReadTimeout > 0 {
+		timer := time.AfterFunc(d.ReadTimeout, func() { cancel(errStalled) })
+		defer timer.Stop()
+		body = &idleReader{r: res.Body, timer: timer, timeout: d.ReadTimeout}
+	}
+
+	written, copyErr := io.Copy(file, body)
+	closeErr := file.Close()
+	if copyErr != nil {
+		if errors.Is(context.Cause(ctx), errStalled) {
+			copyErr = fmt.Errorf("%w for %s", errStalled, d.ReadTimeout)
+		}
+		return fmt.Errorf("download of %s interrupted, rerun to resume: %w", url, copyErr)
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+	if size := offset + written; expected >= 0 && size != expected {
+		return fmt.Errorf("download of %s incomplete, got %d of %d bytes, rerun to resume", url, size, expected)
+	}
+	return nil
+}
+
+// idleReader pushes back timer every time data arrives, so it only fires
+// once the body has been silent for timeout.
+type idleReader struct {
+	r       io.Reader
+	timer   *time.Timer
+	timeout time.Duration
+}
+
+func (r *idleReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.timer.Reset(r.timeout)
+	}
+	return n, err
+}
+
+// parseContentRange splits a Content-Range header such as
+// "bytes 100-199/200" into the first byte position and the total size.
+// Either is -1 when the header leaves it out, as in "bytes */200" or
+// "bytes 0-99/*".
+func parseContentRange(header string) (start, total int64, ok bool) {
+	spec, ok := strings.CutPrefix(header, "bytes ")
+	if !ok {
+		return 0, 0, false
+	}
+	rng, size, ok := strings.Cut(strings.TrimSpace(spec), "/")
+	if !ok {
+		return 0, 0, false
+	}
+
+	start, total = -1, -1
+	if rng != "*" {
+		first, _, ok := strings.Cut(rng, "-")
+		if !ok {
+			return 0, 0, false
+		}
+		n, err := strconv.ParseInt(strings.TrimSpace(first), 10, 64)
+		if err != nil || n < 0 {
+			return 0, 0, false
+		}
+		start = n
+	}
+	if size != "*" {
+		n, err := strconv.ParseInt(strings.TrimSpace(size), 10, 64)
+		if err != nil || n < 0 {
+			return 0, 0, false
+		}
+		total = n
+	}
+	return start, total, true
+}
+
+// FileChecksum returns the hex encoded SHA-256 and size of the file at path.
+func FileChecksum(path string) (string, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
+
+// Verify reports whether the file at path still matches checksum.
+func Verify(path, checksum string) (bool, error) {
+	actual, _, err := FileChecksum(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return actual == checksum, nil
+}
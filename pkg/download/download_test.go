@@ -0,0 +1,154 @@
+package download
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const content = "0123456789abcdefghij"
+
+// serveContent answers like a server that supports ranges, with
+// contentRange deciding the Content-Range of partial responses.
+func serveContent(contentRange func(offset int) string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rng := r.Header.Get("Range")
+		if rng == "" {
+			w.Write([]byte(content))
+			return
+		}
+		offset, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+		if offset >= len(content) {
+			w.Header().Set("Content-Range", "bytes */"+strconv.Itoa(len(content)))
+			w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		header := contentRange(offset)
+		if header != "" {
+			w.Header().Set("Content-Range", header)
+		}
+		w.WriteHeader(http.StatusPartialContent)
+		start, _, _ := parseContentRange(header)
+		w.Write([]byte(content[max(start, 0):]))
+	}
+}
+
+func matchingRange(offset int) string {
+	return "bytes " + strconv.Itoa(offset) + "-19/20"
+}
+
+func TestDownloadResumes(t *testing.T) {
+	tests := []struct {
+		name         string
+		partial      string
+		contentRange func(offset int) string
+	}{
+		{"no partial file", "", matchingRange},
+		{"matching range", content[:8], matchingRange},
+		{"range from the start", content[:8], func(int) string { return "bytes 0-19/20" }},
+		{"missing range", content[:8], func(int) string { return "" }},
+		{"already complete", content, matchingRange},
+		{"partial file longer than the resource", content + "stale", matchingRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(serveContent(tt.contentRange))
+			defer server.Close()
+
+			dest := filepath.Join(t.TempDir(), "file")
+			if tt.partial != "" {
+				if err := os.WriteFile(dest+PartialSuffix, []byte(tt.partial), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			res, err := New().Download(context.Background(), server.URL, dest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := os.ReadFile(res.Path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != content || res.Size != int64(len(content)) {
+				t.Errorf("downloaded %q (%d bytes), want %q", data, res.Size, content)
+			}
+		})
+	}
+}
+
+func TestDownloadIncomplete(t *testing.T) {
+	// The server claims a larger resource than it sends.
+	server := httptest.NewServer(serveContent(func(offset int) string {
+		return "bytes " + strconv.Itoa(offset) + "-29/30"
+	}))
+	defer server.Close()
+
+	dest := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(dest+PartialSuffix, []byte(content[:8]), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := New().Download(context.Background(), server.URL, dest)
+	if err == nil || !strings.Contains(err.Error(), "got 20 of 30 bytes") {
+		t.Fatalf("got %v, want an incomplete download error", err)
+	}
+	if _, err := os.Stat(dest); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("incomplete download was moved into place")
+	}
+}
+
+func TestDownloadStalled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "20")
+		w.Write([]byte(content[:5]))
+		w.(http.Flusher).Flush()
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	d := New()
+	d.ReadTimeout = 50 * time.Millisecond
+	dest := filepath.Join(t.TempDir(), "file")
+	_, err := d.Download(context.Background(), server.URL, dest)
+	if !errors.Is(err, errStalled) {
+		t.Fatalf("got %v, want %v", err, errStalled)
+	}
+	// What arrived is kept for resuming.
+	data, _ := os.ReadFile(dest + PartialSuffix)
+	if string(data) != content[:5] {
+		t.Errorf("partial file holds %q, want %q", data, content[:5])
+	}
+}
+
+func TestParseContentRange(t *testing.T) {
+	tests := []struct {
+		header string
+		start  int64
+		total  int64
+		ok     bool
+	}{
+		{"bytes 100-199/200", 100, 200, true},
+		{"bytes 0-0/*", 0, -1, true},
+		{"bytes */200", -1, 200, true},
+		{"bytes 1-2", 0, 0, false},
+		{"bytes x-2/3", 0, 0, false},
+		{"items 1-2/3", 0, 0, false},
+		{"", 0, 0, false},
+	}
+	for _, tt := range tests {
+		start, total, ok := parseContentRange(tt.header)
+		if start != tt.start || total != tt.total || ok != tt.ok {
+			t.Errorf("parseContentRange(%q) = %d, %d, %v, want %d, %d, %v",
+				tt.header, start, total, ok, tt.start, tt.total, tt.ok)
+		}
+	}
+}
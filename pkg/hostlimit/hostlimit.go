@@ -0,0 +1,132 @@
+package hostlimit
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limiter caps the number of concurrent requests to each host and spaces
+// consecutive requests to the same host by a minimum interval.
+type Limiter struct {
+	maxConcurrent int
+	interval      time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]*host
+}
+
+type host struct {
+	slots        chan struct{}
+	nextAllowed  time.Time
+	backoffUntil time.Time
+}
+
+// BackoffError is returned by Acquire for a host that asked to be left
+// alone until Until. Waiting that out would hold up the caller for as
+// long as the host asked, so Acquire fails instead.
+type BackoffError struct {
+	Host  string
+	Until time.Time
+}
+
+func (e *BackoffError) Error() string {
+	return fmt.Sprintf("%s asked not to be contacted before %s", e.Host, e.Until.Format(time.RFC3339))
+}
+
+func New(maxConcurrent int, interval time.Duration) *Limiter {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+	return &Limiter{
+		maxConcurrent: maxConcurrent,
+		interval:      interval,
+		hosts:         make(map[string]*host),
+	}
+}
+
+// Host returns the key rawURL is rate limited under.
+func Host(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return strings.ToLower(parsed.Hostname())
+}
+
+// Acquire blocks until a request to hostName may start. The returned
+// function must be called once the request has finished. If the host is
+// backed off, Acquire returns a *BackoffError right away.
+func (l *Limiter) Acquire(ctx context.Context, hostName string) (func(), error) {
+	h := l.host(hostName)
+	if err := l.checkBackoff(h, hostName); err != nil {
+		return nil, err
+	}
+
+	select {
+	case h.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	release := func() { <-h.slots }
+
+	for {
+		// The host may have been backed off while we waited.
+		if err := l.checkBackoff(h, hostName); err != nil {
+			release()
+			return nil, err
+		}
+
+		l.mu.Lock()
+		wait := time.Until(h.nextAllowed)
+		if wait <= 0 {
+			h.nextAllowed = time.Now().Add(l.interval)
+			l.mu.Unlock()
+			return release, nil
+		}
+		l.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			release()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Backoff stops requests to hostName from starting before until, e.g.
+// after the host answered with a Retry-After header.
+func (l *Limiter) Backoff(hostName string, until time.Time) {
+	h := l.host(hostName)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until.After(h.backoffUntil) {
+		h.backoffUntil = until
+	}
+}
+
+func (l *Limiter) checkBackoff(h *host, hostName string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if time.Now().Before(h.backoffUntil) {
+		return &BackoffError{Host: hostName, Until: h.backoffUntil}
+	}
+	return nil
+}
+
+func (l *Limiter) host(hostName string) *host {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	h, ok := l.hosts[hostName]
+	if !ok {
+		h = &host{slots: make(chan struct{}, l.maxConcurrent)}
+		l.hosts[hostName] = h
+	}
+	return h
+}
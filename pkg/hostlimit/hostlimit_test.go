@@ -0,0 +1,83 @@
+package hostlimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAcquireFailsFastWhenBackedOff(t *testing.T) {
+	l := New(1, 0)
+	until := time.Now().Add(time.Hour)
+	l.Backoff("example.com", until)
+
+	start := time.Now()
+	_, err := l.Acquire(context.Background(), "example.com")
+	var backoffErr *BackoffError
+	if !errors.As(err, &backoffErr) {
+		t.Fatalf("Acquire error = %v, want *BackoffError", err)
+	}
+	if !backoffErr.Until.Equal(until) {
+		t.Errorf("Until = %v, want %v", backoffErr.Until, until)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Acquire took %v, want it to return right away", elapsed)
+	}
+
+	release, err := l.Acquire(context.Background(), "other.example")
+	if err != nil {
+		t.Fatalf("Acquire on another host: %v", err)
+	}
+	release()
+}
+
+func TestAcquireFailsWhenBackedOffWhileWaiting(t *testing.T) {
+	l := New(1, 0)
+	release, err := l.Acquire(context.Background(), "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error)
+	go func() {
+		_, err := l.Acquire(context.Background(), "example.com")
+		done <- err
+	}()
+
+	// The first request is answered with a Retry-After.
+	time.Sleep(10 * time.Millisecond)
+	l.Backoff("example.com", time.Now().Add(time.Hour))
+	release()
+
+	select {
+	case err := <-done:
+		var backoffErr *BackoffError
+		if !errors.As(err, &backoffErr) {
+			t.Fatalf("Acquire error = %v, want *BackoffError", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Acquire kept waiting on a backed off host")
+	}
+}
+
+func TestAcquireSpacesRequests(t *testing.T) {
+	l := New(1, 50*time.Millisecond)
+	for i := 0; i < 2; i++ {
+		release, err := l.Acquire(context.Background(), "example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		release()
+	}
+
+	start := time.Now()
+	release, err := l.Acquire(context.Background(), "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("third request started after %v, want about 50ms", elapsed)
+	}
+}
@@ -0,0 +1,309 @@
+package markup
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// DefaultWidth is the line width used by RenderText when width is not positive.
+const DefaultWidth = 80
+
+// blockElements start and end a paragraph of their own.
+var blockElements = map[atom.Atom]bool{
+	atom.P:          true,
+	atom.Div:        true,
+	atom.Blockquote: true,
+	atom.Pre:        true,
+	atom.H1:         true,
+	atom.H2:         true,
+	atom.H3:         true,
+	atom.H4:         true,
+	atom.H5:         true,
+	atom.H6:         true,
+	atom.Ul:         true,
+	atom.Ol:         true,
+	atom.Li:         true,
+	atom.Dl:         true,
+	atom.Dt:         true,
+	atom.Dd:         true,
+	atom.Figure:     true,
+	atom.Figcaption: true,
+	atom.Table:      true,
+	atom.Tr:         true,
+	atom.Section:    true,
+	atom.Article:    true,
+	atom.Header:     true,
+	atom.Footer:     true,
+	atom.Aside:      true,
+	atom.Nav:        true,
+}
+
+type list struct {
+	ordered bool
+	next    int
+}
+
+type renderer struct {
+	width  int
+	out    strings.Builder
+	para   strings.Builder
+	indent string
+	marker string
+	pre    int
+	lists  []list
+	links  []string
+}
+
+// RenderText converts an HTML fragment into plain text wrapped at width
+// columns. Links and images are replaced by numbered references that are
+// listed as footnotes after the text.
+func RenderText(fragment string, width int) string {
+	if width <= 0 {
+		width = DefaultWidth
+	}
+
+	nodes, err := parseFragment(fragment)
+	if err != nil {
+		return fragment
+	}
+
+	r := &renderer{width: width}
+	for _, node := range nodes {
+		r.walk(node)
+	}
+	r.flush()
+
+	text := strings.Trim(r.out.String(), "\n")
+	if len(r.links) > 0 {
+		var notes strings.Builder
+		for i, link := range r.links {
+			fmt.Fprintf(&notes, "\n[%d] %s", i+1, link)
+		}
+		text += "\n" + notes.String()
+	}
+	return text
+}
+
+func (r *renderer) walk(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		r.text(n.Data)
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	if droppedElements[n.DataAtom] {
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.Br:
+		r.para.WriteString("\n")
+		return
+	case atom.Hr:
+		r.flush()
+		// Deeply nested quotes can leave no room, keep the rule visible.
+		r.writeLine(r.indent + strings.Repeat("-", max(min(r.width-len(r.indent), 40), 3)))
+		r.out.WriteString("\n")
+		return
+	case atom.Img:
+		alt := strings.TrimSpace(attr(n, "alt"))
+		if alt == "" {
+			alt = "image"
+		}
+		r.text(" [" + alt + "]")
+		r.reference(attr(n, "src"), "")
+		return
+	}
+
+	if !blockElements[n.DataAtom] {
+		r.children(n)
+		if n.DataAtom == atom.A {
+			r.reference(attr(n, "href"), textContent(n))
+		}
+		if n.DataAtom == atom.Td || n.DataAtom == atom.Th {
+			r.text("  ")
+		}
+		return
+	}
+
+	r.flush()
+	switch n.DataAtom {
+	case atom.Blockquote:
+		defer r.setIndent(r.indent)
+		r.indent += "> "
+	case atom.Pre:
+		r.pre++
+		defer func() { r.pre-- }()
+	case atom.Ul, atom.Ol:
+		start := 1
+		if value, err := strconv.Atoi(attr(n, "start")); err == nil {
+			start = value
+		}
+		r.lists = append(r.lists, list{ordered: n.DataAtom == atom.Ol, next: start})
+		defer func() {
+			r.lists = r.lists[:len(r.lists)-1]
+			if len(r.lists) == 0 {
+				r.out.WriteString("\n")
+			}
+		}()
+	case atom.Li:
+		r.marker = "- "
+		if len(r.lists) > 0 {
+			current := &r.lists[len(r.lists)-1]
+			if current.ordered {
+				r.marker = strconv.Itoa(current.next) + ". "
+				current.next++
+			}
+		}
+		// An item without text never flushes its marker, which must not
+		// then be applied to whatever follows the item.
+		defer func(indent string) {
+			r.indent = indent
+			r.marker = ""
+		}(r.indent)
+		r.indent += strings.Repeat(" ", len(r.marker))
+	case atom.Dd:
+		defer r.setIndent(r.indent)
+		r.indent += "    "
+	}
+	r.children(n)
+	r.flush()
+}
+
+func (r *renderer) children(n *html.Node) {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		r.walk(child)
+	}
+}
+
+func (r *renderer) setIndent(indent string) {
+	r.indent = indent
+}
+
+func (r *renderer) text(data string) {
+	if r.pre > 0 {
+		r.para.WriteString(data)
+		return
+	}
+	if data == "" {
+		return
+	}
+	words := strings.Fields(data)
+	if isSpace(data[0]) {
+		r.para.WriteString(" ")
+	}
+	r.para.WriteString(strings.Join(words, " "))
+	if len(words) > 0 && isSpace(data[len(data)-1]) {
+		r.para.WriteString(" ")
+	}
+}
+
+// reference records link as a footnote unless it merely repeats label.
+func (r *renderer) reference(link, label string) {
+	link = strings.TrimSpace(link)
+	if link == "" || strings.HasPrefix(link, "#") || !isSafeURL(link) || link == strings.TrimSpace(label) {
+		return
+	}
+	r.links = append(r.links, link)
+	r.para.WriteString("[" + strconv.Itoa(len(r.links)) + "]")
+}
+
+// flush writes the pending paragraph, wrapped and indented, to the output.
+func (r *renderer) flush() {
+	text := r.para.String()
+	r.para.Reset()
+	if strings.TrimSpace(text) == "" {
+		return
+	}
+
+	prefix := r.indent
+	if r.marker != "" {
+		prefix = r.indent[:len(r.indent)-len(r.marker)] + r.marker
+		r.marker = ""
+	}
+
+	for _, line := range strings.Split(strings.Trim(text, "\n"), "\n") {
+		if r.pre > 0 {
+			r.writeLine(prefix + line)
+		} else {
+			for _, wrapped := range wrap(strings.TrimSpace(line), r.width-len(r.indent)) {
+				r.writeLine(prefix + wrapped)
+				prefix = r.indent
+			}
+		}
+		prefix = r.indent
+	}
+	if len(r.lists) == 0 {
+		r.out.WriteString("\n")
+	}
+}
+
+func (r *renderer) writeLine(line string) {
+	r.out.WriteString(strings.TrimRight(line, " "))
+	r.out.WriteString("\n")
+}
+
+// wrap splits text into lines of at most width characters, breaking only
+// between words.
+func wrap(text string, width int) []string {
+	if width < 20 {
+		width = 20
+	}
+
+	var lines []string
+	var line strings.Builder
+	lineLength := 0
+	for _, word := range strings.Fields(text) {
+		wordLength := len([]rune(word))
+		if lineLength > 0 && lineLength+1+wordLength > width {
+			lines = append(lines, line.String())
+			line.Reset()
+			lineLength = 0
+		}
+		if lineLength > 0 {
+			line.WriteString(" ")
+			lineLength++
+		}
+		line.WriteString(word)
+		lineLength += wordLength
+	}
+	if lineLength > 0 {
+		lines = append(lines, line.String())
+	}
+	return lines
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Namespace == "" && a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func textContent(n *html.Node) string {
+	var text strings.Builder
+	var collect func(*html.Node)
+	collect = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			text.WriteString(n.Data)
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			collect(child)
+		}
+	}
+	collect(n)
+	return text.String()
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == '\f'
+}
@@ -0,0 +1,46 @@
+package markup
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderText(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"empty", "", ""},
+		{"paragraphs", "<p>one</p><p>two</p>", "one\n\ntwo"},
+		{"link footnote", `<p>Hello <a href="https://x.test/a">link</a></p>`, "Hello link[1]\n\n[1] https://x.test/a"},
+		{"blockquote", "<blockquote>quoted</blockquote>", "> quoted"},
+		{"script dropped", "<script>alert(1)</script><p>safe</p>", "safe"},
+		{"list", "<ul><li>a</li><li>b</li></ul>", "- a\n- b"},
+		{"ordered list", `<ol start="3"><li>a</li><li>b</li></ol>`, "3. a\n4. b"},
+		{"empty item", "<ul><li></li></ul><p>hello</p>", "hello"},
+		{"empty ordered item", "<ol><li></li><li>two</li></ol>", "2. two"},
+		{"nested", "<ul><li>a<ul><li>b</li></ul></li><li>c</li></ul>", "- a\n  - b\n- c"},
+		{"empty nested item", "<ul><li>a<ul><li>b</li><li></li></ul></li><li>c</li></ul><p>after</p>", "- a\n  - b\n- c\n\nafter"},
+		{"item holding only a list", "<ul><li><ul><li>inner</li></ul></li></ul>", "  - inner"},
+		{"rule", "<p>a</p><hr><p>b</p>", "a\n\n" + strings.Repeat("-", 40) + "\n\nb"},
+		{"quoted rule", "<blockquote><hr></blockquote>", "> " + strings.Repeat("-", 40)},
+		{"rule nested past the width", strings.Repeat("<blockquote>", 45) + "<hr>", strings.Repeat("> ", 45) + "---"},
+		{"text nested past the width", strings.Repeat("<blockquote>", 45) + "deep", strings.Repeat("> ", 45) + "deep"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderText(tt.in, 80); got != tt.want {
+				t.Errorf("RenderText(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderTextWraps(t *testing.T) {
+	in := "<p>aaaa bbbb cccc dddd eeee ffff gggg</p>"
+	want := "aaaa bbbb cccc dddd\neeee ffff gggg"
+	if got := RenderText(in, 20); got != want {
+		t.Errorf("RenderText(%q, 20) = %q, want %q", in, got, want)
+	}
+}
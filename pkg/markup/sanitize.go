@@ -0,0 +1,186 @@
+package markup
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// allowedAttributes lists the elements kept by Sanitize and the attributes
+// each of them may carry. Elements that are neither allowed nor dropped are
+// unwrapped, keeping their children.
+var allowedAttributes = map[atom.Atom][]string{
+	atom.A:          {"href", "title"},
+	atom.Img:        {"src", "alt", "title", "width", "height"},
+	atom.P:          nil,
+	atom.Br:         nil,
+	atom.Hr:         nil,
+	atom.Div:        nil,
+	atom.Span:       nil,
+	atom.Blockquote: {"cite"},
+	atom.Q:          {"cite"},
+	atom.Cite:       nil,
+	atom.Pre:        nil,
+	atom.Code:       nil,
+	atom.Em:         nil,
+	atom.Strong:     nil,
+	atom.B:          nil,
+	atom.I:          nil,
+	atom.U:          nil,
+	atom.S:          nil,
+	atom.Del:        nil,
+	atom.Ins:        nil,
+	atom.Small:      nil,
+	atom.Sub:        nil,
+	atom.Sup:        nil,
+	atom.Abbr:       {"title"},
+	atom.H1:         nil,
+	atom.H2:         nil,
+	atom.H3:         nil,
+	atom.H4:         nil,
+	atom.H5:         nil,
+	atom.H6:         nil,
+	atom.Ul:         nil,
+	atom.Ol:         {"start"},
+	atom.Li:         nil,
+	atom.Dl:         nil,
+	atom.Dt:         nil,
+	atom.Dd:         nil,
+	atom.Figure:     nil,
+	atom.Figcaption: nil,
+	atom.Table:      nil,
+	atom.Thead:      nil,
+	atom.Tbody:      nil,
+	atom.Tfoot:      nil,
+	atom.Tr:         nil,
+	atom.Th:         {"colspan", "rowspan"},
+	atom.Td:         {"colspan", "rowspan"},
+}
+
+// droppedElements are removed together with everything inside them.
+var droppedElements = map[atom.Atom]bool{
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Iframe:   true,
+	atom.Frame:    true,
+	atom.Frameset: true,
+	atom.Object:   true,
+	atom.Embed:    true,
+	atom.Applet:   true,
+	atom.Form:     true,
+	atom.Input:    true,
+	atom.Button:   true,
+	atom.Textarea: true,
+	atom.Select:   true,
+	atom.Noscript: true,
+	atom.Template: true,
+	atom.Svg:      true,
+	atom.Math:     true,
+	atom.Link:     true,
+	atom.Meta:     true,
+	atom.Base:     true,
+	atom.Head:     true,
+	atom.Title:    true,
+}
+
+// urlAttributes hold URLs and are only kept with a safe scheme.
+var urlAttributes = map[string]bool{
+	"href": true,
+	"src":  true,
+	"cite": true,
+}
+
+var safeSchemes = map[string]bool{
+	"":       true,
+	"http":   true,
+	"https":  true,
+	"mailto": true,
+}
+
+// Sanitize strips scripts, event handlers, styles and any other markup not
+// on the allowlist from an HTML fragment, returning HTML that is safe to
+// embed in a web page.
+func Sanitize(fragment string) string {
+	nodes, err := parseFragment(fragment)
+	if err != nil {
+		return html.EscapeString(fragment)
+	}
+
+	var out strings.Builder
+	for _, node := range nodes {
+		for _, clean := range sanitizeNode(node) {
+			if err := html.Render(&out, clean); err != nil {
+				return html.EscapeString(fragment)
+			}
+		}
+	}
+	return out.String()
+}
+
+// sanitizeNode returns the nodes that replace n in the sanitized tree.
+func sanitizeNode(n *html.Node) []*html.Node {
+	switch n.Type {
+	case html.TextNode:
+		return []*html.Node{{Type: html.TextNode, Data: n.Data}}
+	case html.ElementNode:
+	default:
+		return nil
+	}
+
+	if droppedElements[n.DataAtom] || n.Namespace != "" {
+		return nil
+	}
+
+	var children []*html.Node
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		children = append(children, sanitizeNode(child)...)
+	}
+
+	allowed, ok := allowedAttributes[n.DataAtom]
+	if !ok || n.DataAtom == 0 {
+		return children
+	}
+
+	clean := &html.Node{Type: html.ElementNode, DataAtom: n.DataAtom, Data: n.DataAtom.String()}
+	for _, attr := range n.Attr {
+		if attr.Namespace != "" || !contains(allowed, attr.Key) {
+			continue
+		}
+		if urlAttributes[attr.Key] && !isSafeURL(attr.Val) {
+			continue
+		}
+		clean.Attr = append(clean.Attr, html.Attribute{Key: attr.Key, Val: attr.Val})
+	}
+	if n.DataAtom == atom.A {
+		clean.Attr = append(clean.Attr, html.Attribute{Key: "rel", Val: "nofollow noopener noreferrer"})
+	}
+
+	for _, child := range children {
+		clean.AppendChild(child)
+	}
+	return []*html.Node{clean}
+}
+
+func isSafeURL(value string) bool {
+	parsed, err := url.Parse(strings.TrimSpace(value))
+	if err != nil {
+		return false
+	}
+	return safeSchemes[strings.ToLower(parsed.Scheme)]
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func parseFragment(fragment string) ([]*html.Node, error) {
+	body := &html.Node{Type: html.ElementNode, DataAtom: atom.Body, Data: "body"}
+	return html.ParseFragment(strings.NewReader(fragment), body)
+}
@@ -0,0 +1,27 @@
+package markup
+
+import "testing"
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"empty", "", ""},
+		{"event handler and script", `<p onclick="x()">hi<script>bad()</script></p>`, "<p>hi</p>"},
+		{"unsafe link", `<a href="javascript:alert(1)">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{"safe link", `<a href="https://x.test">x</a>`, `<a href="https://x.test" rel="nofollow noopener noreferrer">x</a>`},
+		{"unknown element unwrapped", "<font color=red>text</font>", "text"},
+		{"image attributes", `<img src="/a.png" onerror="x">`, `<img src="/a.png"/>`},
+		{"empty item", "<ul><li></li></ul>", "<ul><li></li></ul>"},
+		{"nested items", "<ul><li>a<ul><li>b</li><li></li></ul></li></ul>", "<ul><li>a<ul><li>b</li><li></li></ul></li></ul>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sanitize(tt.in); got != tt.want {
+				t.Errorf("Sanitize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package rss
+
+import (
+	"encoding/xml"
+	"html"
+	"strings"
+)
+
+const atomNamespace = "http://www.w3.org/2005/Atom"
+
+type atomFeed struct {
+	Base     string      `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
+	Title    atomText    `xml:"http://www.w3.org/2005/Atom title"`
+	Subtitle atomText    `xml:"http://www.w3.org/2005/Atom subtitle"`
+	Links    []Link      `xml:"http://www.w3.org/2005/Atom link"`
+	Entries  []atomEntry `xml:"http://www.w3.org/2005/Atom entry"`
+}
+
+type atomEntry struct {
+	Base       string         `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
+	Title      atomText       `xml:"http://www.w3.org/2005/Atom title"`
+	Links      []Link         `xml:"http://www.w3.org/2005/Atom link"`
+	Published  string         `xml:"http://www.w3.org/2005/Atom published"`
+	Updated    string         `xml:"http://www.w3.org/2005/Atom updated"`
+	Summary    atomText       `xml:"http://www.w3.org/2005/Atom summary"`
+	Content    atomText       `xml:"http://www.w3.org/2005/Atom content"`
+	Authors    []atomPerson   `xml:"http://www.w3.org/2005/Atom author"`
+	Categories []atomCategory `xml:"http://www.w3.org/2005/Atom category"`
+}
+
+// atomText is an Atom text construct, which holds plain text, escaped HTML
+// or inline XHTML depending on its type attribute.
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Text  string `xml:",chardata"`
+	Inner string `xml:",innerxml"`
+}
+
+type atomPerson struct {
+	Name string `xml:"http://www.w3.org/2005/Atom name"`
+}
+
+type atomCategory struct {
+	Term  string `xml:"term,attr"`
+	Label string `xml:"label,attr"`
+}
+
+func (t *atomText) plain() string {
+	return strings.TrimSpace(t.Text)
+}
+
+func (t *atomText) html() string {
+	switch t.Type {
+	case "html":
+		return t.Text
+	case "xhtml":
+		return strings.TrimSpace(t.Inner)
+	default:
+		return html.EscapeString(t.Text)
+	}
+}
+
+func parseAtom(decoder *xml.Decoder, root *xml.StartElement) (*Feed, error) {
+	var atom atomFeed
+	if err := decoder.DecodeElement(&atom, root); err != nil {
+		return nil, err
+	}
+
+	feed := &Feed{Format: FormatAtom, Base: atom.Base}
+	feed.Channel.Title = atom.Title.plain()
+	feed.Channel.Description = atom.Subtitle.plain()
+	feed.Channel.Links = atom.Links
+	feed.Channel.Link = atomAlternate(atom.Links)
+
+	for _, entry := range atom.Entries {
+		item := Item{
+			Base:    entry.Base,
+			Title:   entry.Title.plain(),
+			Links:   entry.Links,
+			Link:    atomAlternate(entry.Links),
+			PubDate: entry.Published,
+		}
+		if item.PubDate == "" {
+			item.PubDate = entry.Updated
+		}
+		if entry.Content.Text != "" || entry.Content.Inner != "" {
+			item.Description = entry.Content.html()
+		} else {
+			item.Description = entry.Summary.html()
+		}
+		for _, author := range entry.Authors {
+			item.Authors = append(item.Authors, Element{Value: author.Name})
+		}
+		for _, category := range entry.Categories {
+			if category.Label != "" {
+				item.Categories = append(item.Categories, category.Label)
+			} else {
+				item.Categories = append(item.Categories, category.Term)
+			}
+		}
+		for _, link := range entry.Links {
+			if link.Rel == "enclosure" {
+				item.Enclosures = append(item.Enclosures, Enclosure{URL: link.Href, Length: link.Length, Type: link.Type})
+			}
+		}
+		feed.Channel.Items = append(feed.Channel.Items, item)
+	}
+	return feed, nil
+}
+
+// atomAlternate returns the href of the alternate link, which is also the
+// meaning of a link without rel.
+func atomAlternate(links []Link) string {
+	for _, link := range links {
+		if link.Rel == "" || link.Rel == "alternate" {
+			return strings.TrimSpace(link.Href)
+		}
+	}
+	return ""
+}
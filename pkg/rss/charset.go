@@ -0,0 +1,59 @@
+package rss
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"mime"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html/charset"
+)
+
+// newXMLDecoder returns a decoder that transcodes data to UTF-8. A charset
+// in the Content-Type header takes precedence over the XML prolog, except
+// that a "utf-8" header is ignored when the body is not valid UTF-8, since
+// many servers attach that default regardless of the document's encoding.
+func newXMLDecoder(data []byte, contentType string) (*xml.Decoder, error) {
+	var reader io.Reader = bytes.NewReader(data)
+	transcoded := false
+
+	if label := charsetFromContentType(contentType); label != "" {
+		if !isUTF8Label(label) {
+			r, err := charset.NewReaderLabel(label, reader)
+			if err != nil {
+				return nil, err
+			}
+			reader = r
+			transcoded = true
+		} else if utf8.Valid(data) {
+			transcoded = true
+		}
+	}
+
+	decoder := xml.NewDecoder(reader)
+	decoder.CharsetReader = func(label string, input io.Reader) (io.Reader, error) {
+		if transcoded {
+			return input, nil
+		}
+		return charset.NewReaderLabel(label, input)
+	}
+	return decoder, nil
+}
+
+func charsetFromContentType(contentType string) string {
+	if contentType == "" {
+		return ""
+	}
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(params["charset"])
+}
+
+func isUTF8Label(label string) bool {
+	label = strings.ToLower(label)
+	return label == "utf-8" || label == "utf8"
+}
@@ -0,0 +1,83 @@
+package rss
+
+import "testing"
+
+func TestParseCharset(t *testing.T) {
+	const utf8Prolog = `<?xml version="1.0" encoding="UTF-8"?>`
+	const latin1Prolog = `<?xml version="1.0" encoding="ISO-8859-1"?>`
+	body := func(prolog, title string) []byte {
+		return []byte(prolog + "<rss><channel><title>" + title + "</title></channel></rss>")
+	}
+
+	tests := []struct {
+		name        string
+		data        []byte
+		contentType string
+		want        string
+	}{
+		{
+			name: "utf-8 without hints",
+			data: body("", "Caf\xc3\xa9"),
+			want: "Café",
+		},
+		{
+			name: "charset from the prolog",
+			data: body(latin1Prolog, "Caf\xe9"),
+			want: "Café",
+		},
+		{
+			name:        "charset from Content-Type",
+			data:        body("", "Caf\xe9"),
+			contentType: "application/rss+xml; charset=iso-8859-1",
+			want:        "Café",
+		},
+		{
+			name:        "Content-Type beats the prolog",
+			data:        body(utf8Prolog, "Caf\xe9"),
+			contentType: "text/xml; charset=ISO-8859-1",
+			want:        "Café",
+		},
+		{
+			name:        "windows-1252 punctuation",
+			data:        body("", "\x93quoted\x94 \x80"),
+			contentType: "text/xml; charset=windows-1252",
+			want:        "“quoted” €",
+		},
+		{
+			name:        "utf-8 Content-Type on a latin-1 body",
+			data:        body(latin1Prolog, "Caf\xe9"),
+			contentType: "text/xml; charset=utf-8",
+			want:        "Café",
+		},
+		{
+			name:        "utf-8 Content-Type and a stale prolog",
+			data:        body(latin1Prolog, "Caf\xc3\xa9"),
+			contentType: "text/xml; charset=UTF-8",
+			want:        "Café",
+		},
+		{
+			name:        "malformed Content-Type falls back to the prolog",
+			data:        body(latin1Prolog, "Caf\xe9"),
+			contentType: "text/xml; charset",
+			want:        "Café",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed, err := Parse(tt.data, tt.contentType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if feed.Channel.Title != tt.want {
+				t.Errorf("title = %q, want %q", feed.Channel.Title, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseUnknownCharset(t *testing.T) {
+	data := []byte("<rss><channel><title>x</title></channel></rss>")
+	if _, err := Parse(data, "text/xml; charset=klingon"); err == nil {
+		t.Error("Parse accepted an unknown charset")
+	}
+}
@@ -0,0 +1,115 @@
+package rss
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Report is the outcome of checking a feed URL. Problems prevent the feed
+// from being used; warnings describe things that will work, but poorly.
+type Report struct {
+	URL      string
+	Feed     *Feed
+	Problems []string
+	Warnings []string
+}
+
+// OK reports whether the URL can be used as a feed.
+func (r *Report) OK() bool {
+	return len(r.Problems) == 0
+}
+
+// Err summarises the problems found as a single error, or returns nil.
+func (r *Report) Err() error {
+	if r.OK() {
+		return nil
+	}
+	return fmt.Errorf("%s is not a usable feed:\n  - %s", r.URL, strings.Join(r.Problems, "\n  - "))
+}
+
+// ValidateURL checks that rawURL is an absolute http or https URL.
+func ValidateURL(rawURL string) error {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return fmt.Errorf("URL cannot be parsed: %v", err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		if parsed.Scheme == "" {
+			return fmt.Errorf("URL has no scheme, did you mean https://%s?", rawURL)
+		}
+		return fmt.Errorf("URL scheme %q is not supported, use http or https", parsed.Scheme)
+	}
+	if parsed.Hostname() == "" {
+		return errors.New("URL has no host")
+	}
+	return nil
+}
+
+// CheckWith validates feedURL, fetches it with fetcher and inspects the
+// result, collecting every problem found rather than stopping at the first.
+func CheckWith(ctx context.Context, fetcher FeedFetcher, feedURL string) *Report {
+	report := &Report{URL: feedURL}
+	if err := ValidateURL(feedURL); err != nil {
+		report.Problems = append(report.Problems, err.Error())
+		return report
+	}
+
+	feed, err := fetcher.Fetch(ctx, feedURL)
+	if err != nil {
+		report.Problems = append(report.Problems, describeFetchError(err))
+		return report
+	}
+	report.Feed = feed
+
+	if feed.PermanentURL != "" {
+		report.Warnings = append(report.Warnings,
+			fmt.Sprintf("URL redirects permanently to %s, consider using that instead", feed.PermanentURL))
+	}
+	if strings.TrimSpace(feed.Channel.Title) == "" {
+		report.Warnings = append(report.Warnings, "feed has no title")
+	}
+	if len(feed.Channel.Items) == 0 {
+		report.Warnings = append(report.Warnings, "feed has no items")
+	}
+
+	missingLinks, badDates := 0, 0
+	for i := range feed.Channel.Items {
+		item := &feed.Channel.Items[i]
+		if strings.TrimSpace(item.Link) == "" {
+			missingLinks++
+		}
+		if _, err := item.PublishedTime(); err != nil {
+			badDates++
+		}
+	}
+	if missingLinks > 0 {
+		report.Warnings = append(report.Warnings,
+			fmt.Sprintf("%d item(s) have no link and will be skipped", missingLinks))
+	}
+	if badDates > 0 {
+		report.Warnings = append(report.Warnings,
+			fmt.Sprintf("%d item(s) have a missing or unrecognized publication date and will be skipped", badDates))
+	}
+	return report
+}
+
+func describeFetchError(err error) string {
+	var statusErr *StatusError
+	var notFeed *NotAFeedError
+	var timeout interface{ Timeout() bool }
+	switch {
+	case errors.As(err, &statusErr):
+		return fmt.Sprintf("server answered %s", statusErr.Status)
+	case errors.As(err, &notFeed):
+		return fmt.Sprintf("response is not RSS, Atom or JSON Feed: %s", notFeed.Reason)
+	case errors.Is(err, ErrBodyTooLarge):
+		return "response is larger than the maximum feed size"
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &timeout) && timeout.Timeout():
+		return "server did not respond in time"
+	default:
+		return fmt.Sprintf("could not fetch feed: %v", err)
+	}
+}
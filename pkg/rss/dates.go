@@ -0,0 +1,35 @@
+package rss
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// dateLayouts are the date formats seen in the wild, RSS's RFC 822 variants
+// first and Atom/JSON Feed's RFC 3339 after.
+var dateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"Mon, 02 Jan 2006 15:04 -0700",
+	"2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04:05 MST",
+	time.RFC822Z,
+	time.RFC822,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// PublishedTime parses the item's publication date.
+func (i *Item) PublishedTime() (time.Time, error) {
+	value := strings.TrimSpace(i.PubDate)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized date %q", i.PubDate)
+}
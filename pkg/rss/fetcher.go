@@ -0,0 +1,260 @@
+package rss
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andybalholm/brotli"
+)
+
+// DefaultUserAgent identifies gator to the servers it fetches feeds from.
+const DefaultUserAgent = "gator/1.0 (+https://github.com/Shubham-Hazra/blog-aggregator)"
+
+const maxRedirects = 10
+
+const acceptHeader = "application/rss+xml, application/atom+xml, application/xml;q=0.9, text/xml;q=0.9, */*;q=0.8"
+
+// ErrBodyTooLarge is returned when a response exceeds FetcherOptions.MaxBodySize.
+var ErrBodyTooLarge = errors.New("response body too large")
+
+// StatusError reports a non-2xx response from a feed server.
+type StatusError struct {
+	URL        string
+	StatusCode int
+	Status     string
+	// RetryAfter is the delay requested by a Retry-After header, or zero.
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("fetching %s: unexpected status %s", e.URL, e.Status)
+}
+
+// FetcherOptions configures a Fetcher. Zero values fall back to the
+// corresponding field of DefaultFetcherOptions.
+type FetcherOptions struct {
+	// ConnectTimeout bounds establishing the TCP connection and TLS handshake.
+	ConnectTimeout time.Duration
+	// Timeout bounds the whole request, including reading the body.
+	Timeout time.Duration
+	// MaxBodySize is the largest decompressed body accepted, in bytes.
+	MaxBodySize int64
+	UserAgent   string
+}
+
+func DefaultFetcherOptions() FetcherOptions {
+	return FetcherOptions{
+		ConnectTimeout: 10 * time.Second,
+		Timeout:        30 * time.Second,
+		MaxBodySize:    10 << 20,
+		UserAgent:      DefaultUserAgent,
+	}
+}
+
+// FeedFetcher fetches and parses feeds. Fetcher does so over HTTP,
+// Recorder and Replayer save and serve its responses for offline use.
+type FeedFetcher interface {
+	Fetch(ctx context.Context, feedURL string) (*Feed, error)
+}
+
+// Fetcher downloads and parses feeds over HTTP.
+type Fetcher struct {
+	client      *http.Client
+	userAgent   string
+	maxBodySize int64
+}
+
+func NewFetcher(opts FetcherOptions) *Fetcher {
+	defaults := DefaultFetcherOptions()
+	if opts.ConnectTimeout <= 0 {
+		opts.ConnectTimeout = defaults.ConnectTimeout
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaults.Timeout
+	}
+	if opts.MaxBodySize <= 0 {
+		opts.MaxBodySize = defaults.MaxBodySize
+	}
+	if opts.UserAgent == "" {
+		opts.UserAgent = defaults.UserAgent
+	}
+
+	dialer := &net.Dialer{Timeout: opts.ConnectTimeout}
+	transport := &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: opts.ConnectTimeout,
+		// Compression is negotiated and decoded by Fetch so that brotli
+		// is supported and the size limit applies to the decoded body.
+		DisableCompression: true,
+		MaxIdleConns:       100,
+		IdleConnTimeout:    90 * time.Second,
+	}
+
+	return &Fetcher{
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   opts.Timeout,
+		},
+		userAgent:   opts.UserAgent,
+		maxBodySize: opts.MaxBodySize,
+	}
+}
+
+// Fetch downloads feedURL and parses it into a Feed.
+func (f *Fetcher) Fetch(ctx context.Context, feedURL string) (*Feed, error) {
+	res, err := f.Get(ctx, feedURL)
+	if err != nil {
+		return nil, err
+	}
+	return res.Feed()
+}
+
+// Get downloads feedURL without parsing it. Non-2xx responses are
+// returned rather than treated as errors, so that they can be recorded.
+func (f *Fetcher) Get(ctx context.Context, feedURL string) (*Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", f.userAgent)
+	req.Header.Set("Accept", acceptHeader)
+	req.Header.Set("Accept-Encoding", "gzip, br")
+
+	// Track whether every hop of a redirect chain was permanent, in which
+	// case the caller should update the stored URL of the feed.
+	permanent := true
+	client := *f.client
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		if code := req.Response.StatusCode; code != http.StatusMovedPermanently && code != http.StatusPermanentRedirect {
+			permanent = false
+		}
+		return nil
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	finalURL := res.Request.URL.String()
+	response := &Response{
+		URL:               feedURL,
+		FinalURL:          finalURL,
+		PermanentRedirect: permanent && finalURL != feedURL,
+		StatusCode:        res.StatusCode,
+		Status:            res.Status,
+		ContentType:       res.Header.Get("Content-Type"),
+		RetryAfter:        res.Header.Get("Retry-After"),
+	}
+	if !response.ok() {
+		return response, nil
+	}
+
+	response.Body, err = f.readBody(res)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", feedURL, err)
+	}
+	return response, nil
+}
+
+// Response is a feed as served, before parsing. Body is decompressed.
+type Response struct {
+	URL      string
+	FinalURL string
+	// PermanentRedirect is set when URL redirected to FinalURL and every
+	// redirect on the way was permanent.
+	PermanentRedirect bool
+	StatusCode        int
+	Status            string
+	ContentType       string
+	RetryAfter        string
+	Body              []byte
+}
+
+func (r *Response) ok() bool {
+	return r.StatusCode >= 200 && r.StatusCode <= 299
+}
+
+// Feed parses the response, returning a *StatusError for non-2xx
+// responses.
+func (r *Response) Feed() (*Feed, error) {
+	if !r.ok() {
+		return nil, &StatusError{
+			URL:        r.URL,
+			StatusCode: r.StatusCode,
+			Status:     r.Status,
+			RetryAfter: parseRetryAfter(r.RetryAfter, time.Now()),
+		}
+	}
+
+	feed, err := Parse(r.Body, r.ContentType)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.PermanentRedirect {
+		feed.PermanentURL = r.FinalURL
+	}
+
+	feed.ResolveURLs(r.FinalURL)
+	return feed, nil
+}
+
+func (f *Fetcher) readBody(res *http.Response) ([]byte, error) {
+	var body io.Reader = res.Body
+	switch strings.ToLower(strings.TrimSpace(res.Header.Get("Content-Encoding"))) {
+	case "", "identity":
+	case "gzip", "x-gzip":
+		gz, err := gzip.NewReader(res.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		body = gz
+	case "br":
+		body = brotli.NewReader(res.Body)
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", res.Header.Get("Content-Encoding"))
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, f.maxBodySize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > f.maxBodySize {
+		return nil, ErrBodyTooLarge
+	}
+	return data, nil
+}
+
+// parseRetryAfter understands both forms of Retry-After: a number of
+// seconds or an HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
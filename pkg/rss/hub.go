@@ -0,0 +1,25 @@
+package rss
+
+import "strings"
+
+// HubURLs returns the WebSub hubs the feed advertises with rel="hub" links.
+func (f *Feed) HubURLs() []string {
+	var hubs []string
+	for _, link := range f.Channel.Links {
+		if strings.EqualFold(link.Rel, "hub") && strings.TrimSpace(link.Href) != "" {
+			hubs = append(hubs, strings.TrimSpace(link.Href))
+		}
+	}
+	return hubs
+}
+
+// SelfURL returns the feed's canonical URL from its rel="self" link, which
+// is the topic URL to use when subscribing at a hub.
+func (f *Feed) SelfURL() string {
+	for _, link := range f.Channel.Links {
+		if strings.EqualFold(link.Rel, "self") && strings.TrimSpace(link.Href) != "" {
+			return strings.TrimSpace(link.Href)
+		}
+	}
+	return ""
+}
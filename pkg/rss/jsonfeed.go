@@ -0,0 +1,120 @@
+package rss
+
+import (
+	"encoding/json"
+	"html"
+	"strconv"
+	"strings"
+)
+
+const jsonFeedVersionPrefix = "https://jsonfeed.org/version/"
+
+type jsonFeed struct {
+	Version     string     `json:"version"`
+	Title       string     `json:"title"`
+	HomePageURL string     `json:"home_page_url"`
+	FeedURL     string     `json:"feed_url"`
+	Description string     `json:"description"`
+	Hubs        []jsonHub  `json:"hubs"`
+	Items       []jsonItem `json:"items"`
+}
+
+type jsonHub struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+type jsonItem struct {
+	ID            string           `json:"id"`
+	URL           string           `json:"url"`
+	ExternalURL   string           `json:"external_url"`
+	Title         string           `json:"title"`
+	ContentHTML   string           `json:"content_html"`
+	ContentText   string           `json:"content_text"`
+	Summary       string           `json:"summary"`
+	DatePublished string           `json:"date_published"`
+	DateModified  string           `json:"date_modified"`
+	Author        *jsonAuthor      `json:"author"`
+	Authors       []jsonAuthor     `json:"authors"`
+	Tags          []string         `json:"tags"`
+	Attachments   []jsonAttachment `json:"attachments"`
+}
+
+type jsonAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonAttachment struct {
+	URL               string  `json:"url"`
+	MimeType          string  `json:"mime_type"`
+	SizeInBytes       int64   `json:"size_in_bytes"`
+	DurationInSeconds float64 `json:"duration_in_seconds"`
+}
+
+func parseJSONFeed(data []byte) (*Feed, error) {
+	var doc jsonFeed
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, &NotAFeedError{Reason: "document is not valid JSON: " + err.Error()}
+	}
+	if !strings.HasPrefix(doc.Version, jsonFeedVersionPrefix) {
+		return nil, &NotAFeedError{Reason: "JSON document has no JSON Feed version"}
+	}
+
+	feed := &Feed{Format: FormatJSON}
+	feed.Channel.Title = doc.Title
+	feed.Channel.Link = doc.HomePageURL
+	feed.Channel.Description = doc.Description
+	if doc.FeedURL != "" {
+		feed.Channel.Links = append(feed.Channel.Links, Link{Rel: "self", Href: doc.FeedURL})
+	}
+	for _, hub := range doc.Hubs {
+		if strings.EqualFold(hub.Type, "websub") {
+			feed.Channel.Links = append(feed.Channel.Links, Link{Rel: "hub", Href: hub.URL})
+		}
+	}
+
+	for _, entry := range doc.Items {
+		item := Item{
+			Title:   entry.Title,
+			Link:    entry.URL,
+			PubDate: entry.DatePublished,
+		}
+		if item.Link == "" {
+			item.Link = entry.ExternalURL
+		}
+		if item.PubDate == "" {
+			item.PubDate = entry.DateModified
+		}
+
+		switch {
+		case entry.ContentHTML != "":
+			item.Description = entry.ContentHTML
+		case entry.ContentText != "":
+			item.Description = html.EscapeString(entry.ContentText)
+		default:
+			item.Description = html.EscapeString(entry.Summary)
+		}
+
+		authors := entry.Authors
+		if len(authors) == 0 && entry.Author != nil {
+			authors = []jsonAuthor{*entry.Author}
+		}
+		for _, author := range authors {
+			item.Authors = append(item.Authors, Element{Value: author.Name})
+		}
+		item.Categories = entry.Tags
+
+		for _, attachment := range entry.Attachments {
+			enclosure := Enclosure{URL: attachment.URL, Type: attachment.MimeType}
+			if attachment.SizeInBytes > 0 {
+				enclosure.Length = strconv.FormatInt(attachment.SizeInBytes, 10)
+			}
+			if attachment.DurationInSeconds > 0 && item.Duration == "" {
+				item.Duration = strconv.Itoa(int(attachment.DurationInSeconds))
+			}
+			item.Enclosures = append(item.Enclosures, enclosure)
+		}
+		feed.Channel.Items = append(feed.Channel.Items, item)
+	}
+	return feed, nil
+}
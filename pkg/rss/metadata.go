@@ -0,0 +1,67 @@
+package rss
+
+import (
+	"encoding/xml"
+	"regexp"
+	"strings"
+)
+
+// Element captures an element's text along with its namespace, for tags
+// like author that appear both in plain RSS and in extension namespaces.
+type Element struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+// RSS authors are usually written as "email (Full Name)".
+var rssAuthorPattern = regexp.MustCompile(`^\S+@\S+\s+\((.+)\)$`)
+
+// AuthorName returns the item's author, preferring dc:creator over the RSS
+// author element and falling back to itunes:author.
+func (i *Item) AuthorName() string {
+	for _, creator := range i.Creators {
+		if name := strings.TrimSpace(creator); name != "" {
+			return name
+		}
+	}
+
+	fallback := ""
+	for _, author := range i.Authors {
+		name := strings.TrimSpace(author.Value)
+		if name == "" {
+			continue
+		}
+		if author.XMLName.Space != "" {
+			if fallback == "" {
+				fallback = name
+			}
+			continue
+		}
+		if match := rssAuthorPattern.FindStringSubmatch(name); match != nil {
+			return strings.TrimSpace(match[1])
+		}
+		return name
+	}
+	return fallback
+}
+
+// CategoryNames returns the item's categories normalized to lower case with
+// duplicates and empty values removed.
+func (i *Item) CategoryNames() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, category := range i.Categories {
+		name := NormalizeCategory(category)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+// NormalizeCategory maps a category label to the form it is stored under.
+func NormalizeCategory(category string) string {
+	return strings.ToLower(strings.Join(strings.Fields(category), " "))
+}
@@ -0,0 +1,78 @@
+package rss
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Enclosure is a media file attached to an item, usually a podcast episode.
+type Enclosure struct {
+	URL    string `xml:"url,attr"`
+	Length string `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+// ITunesImage is the artwork referenced by an itunes:image element.
+type ITunesImage struct {
+	Href string `xml:"href,attr"`
+}
+
+// Size returns the declared length of the enclosure in bytes. Publishers
+// frequently leave it empty or set it to 0, in which case ok is false.
+func (e *Enclosure) Size() (int64, bool) {
+	n, err := strconv.ParseInt(strings.TrimSpace(e.Length), 10, 64)
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// DurationSeconds parses itunes:duration, which may be given as plain
+// seconds, MM:SS or HH:MM:SS.
+func (i *Item) DurationSeconds() (int, bool) {
+	value := strings.TrimSpace(i.Duration)
+	if value == "" {
+		return 0, false
+	}
+
+	parts := strings.Split(value, ":")
+	if len(parts) > 3 {
+		return 0, false
+	}
+
+	seconds := 0
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0, false
+		}
+		seconds = seconds*60 + n
+	}
+	return seconds, true
+}
+
+// EpisodeNumber returns the itunes:episode number, if present.
+func (i *Item) EpisodeNumber() (int, bool) {
+	return parsePositiveInt(i.Episode)
+}
+
+// SeasonNumber returns the itunes:season number, if present.
+func (i *Item) SeasonNumber() (int, bool) {
+	return parsePositiveInt(i.Season)
+}
+
+// ImageURL returns the episode artwork, falling back to the channel artwork.
+func (i *Item) ImageURL(feed *Feed) string {
+	if i.Image.Href != "" {
+		return i.Image.Href
+	}
+	return feed.Channel.Image.Href
+}
+
+func parsePositiveInt(value string) (int, bool) {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,157 @@
+package rss
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrNotRecorded is returned by a Replayer for URLs it has no recording of.
+var ErrNotRecorded = errors.New("no recorded response")
+
+// recording is the metadata of a saved response. The body is stored next
+// to it, unmodified, so it can be inspected and edited by hand.
+type recording struct {
+	URL        string    `json:"url"`
+	RecordedAt time.Time `json:"recorded_at"`
+	// Error is set when the request failed without a response.
+	Error             string `json:"error,omitempty"`
+	FinalURL          string `json:"final_url,omitempty"`
+	PermanentRedirect bool   `json:"permanent_redirect,omitempty"`
+	StatusCode        int    `json:"status_code,omitempty"`
+	Status            string `json:"status,omitempty"`
+	ContentType       string `json:"content_type,omitempty"`
+	RetryAfter        string `json:"retry_after,omitempty"`
+}
+
+// Recorder is a FeedFetcher that saves every response it gets, including
+// errors, to a directory a Replayer can later serve them from. A new
+// response for a URL replaces the previous one.
+type Recorder struct {
+	fetcher *Fetcher
+	dir     string
+}
+
+func NewRecorder(fetcher *Fetcher, dir string) *Recorder {
+	return &Recorder{fetcher: fetcher, dir: dir}
+}
+
+func (r *Recorder) Fetch(ctx context.Context, feedURL string) (*Feed, error) {
+	res, err := r.fetcher.Get(ctx, feedURL)
+	if err != nil {
+		// Our own cancellation says nothing about the server.
+		if ctx.Err() == nil {
+			r.save(&recording{URL: feedURL, RecordedAt: time.Now().UTC(), Error: err.Error()}, nil)
+		}
+		return nil, err
+	}
+
+	r.save(&recording{
+		URL:               feedURL,
+		RecordedAt:        time.Now().UTC(),
+		FinalURL:          res.FinalURL,
+		PermanentRedirect: res.PermanentRedirect,
+		StatusCode:        res.StatusCode,
+		Status:            res.Status,
+		ContentType:       res.ContentType,
+		RetryAfter:        res.RetryAfter,
+	}, res.Body)
+	return res.Feed()
+}
+
+// save writes rec and body to the recording directory. Failing to record
+// must not fail the fetch, so errors are only logged.
+func (r *Recorder) save(rec *recording, body []byte) {
+	if err := writeRecording(r.dir, rec, body); err != nil {
+		log.Printf("rss: recording response of %s: %v\n", rec.URL, err)
+	}
+}
+
+func writeRecording(dir string, rec *recording, body []byte) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	meta, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return err
+	}
+	base := filepath.Join(dir, recordingName(rec.URL))
+	// The body goes first: a recording only counts once its metadata
+	// exists, so a partly written one is never replayed.
+	if err := os.WriteFile(base+".body", body, 0644); err != nil {
+		return err
+	}
+	return os.WriteFile(base+".json", append(meta, '\n'), 0644)
+}
+
+// Replayer is a FeedFetcher that serves the responses saved by a Recorder
+// instead of touching the network.
+type Replayer struct {
+	dir string
+}
+
+func NewReplayer(dir string) *Replayer {
+	return &Replayer{dir: dir}
+}
+
+func (r *Replayer) Fetch(ctx context.Context, feedURL string) (*Feed, error) {
+	base := filepath.Join(r.dir, recordingName(feedURL))
+	meta, err := os.ReadFile(base + ".json")
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w for %s in %s", ErrNotRecorded, feedURL, r.dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rec recording
+	if err := json.Unmarshal(meta, &rec); err != nil {
+		return nil, fmt.Errorf("reading recording of %s: %w", feedURL, err)
+	}
+	if rec.Error != "" {
+		return nil, errors.New(rec.Error)
+	}
+
+	body, err := os.ReadFile(base + ".body")
+	if err != nil {
+		return nil, fmt.Errorf("reading recording of %s: %w", feedURL, err)
+	}
+
+	res := &Response{
+		URL:               feedURL,
+		FinalURL:          rec.FinalURL,
+		PermanentRedirect: rec.PermanentRedirect,
+		StatusCode:        rec.StatusCode,
+		Status:            rec.Status,
+		ContentType:       rec.ContentType,
+		RetryAfter:        rec.RetryAfter,
+		Body:              body,
+	}
+	return res.Feed()
+}
+
+// recordingName derives the file name a URL is recorded under: the host,
+// to make the directory browsable, and a hash of the full URL.
+func recordingName(feedURL string) string {
+	sum := sha256.Sum256([]byte(feedURL))
+	host := "feed"
+	if parsed, err := url.Parse(feedURL); err == nil && parsed.Hostname() != "" {
+		host = strings.Map(func(r rune) rune {
+			if r == '.' || r == '-' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+				return r
+			}
+			return '_'
+		}, strings.ToLower(parsed.Hostname()))
+	}
+	return host + "-" + hex.EncodeToString(sum[:8])
+}
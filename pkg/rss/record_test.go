@@ -0,0 +1,127 @@
+package rss
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const recordedFeed = `<?xml version="1.0"?>
+<rss version="2.0">
+<channel>
+  <title>Recorded</title>
+  <link>https://example.com/</link>
+  <item>
+    <title>First</title>
+    <link>https://example.com/1</link>
+    <pubDate>Mon, 02 Jan 2006 15:04:05 GMT</pubDate>
+  </item>
+</channel>
+</rss>`
+
+type fetchResult struct {
+	feed *Feed
+	err  error
+}
+
+func TestRecordReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/feed":
+			w.Header().Set("Content-Type", "application/rss+xml")
+			w.Write([]byte(recordedFeed))
+		case "/old":
+			http.Redirect(w, r, "/feed", http.StatusMovedPermanently)
+		case "/older":
+			http.Redirect(w, r, "/old", http.StatusPermanentRedirect)
+		case "/temporary":
+			http.Redirect(w, r, "/feed", http.StatusFound)
+		case "/gone":
+			http.Error(w, "gone", http.StatusGone)
+		case "/busy":
+			w.Header().Set("Retry-After", "120")
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	// A server that is no longer listening produces a transport error.
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	urls := []string{server.URL + "/feed", server.URL + "/gone", server.URL + "/busy", closed.URL + "/feed"}
+	redirects := map[string]string{
+		server.URL + "/old":       server.URL + "/feed",
+		server.URL + "/older":     server.URL + "/feed",
+		server.URL + "/temporary": "",
+	}
+	for url := range redirects {
+		urls = append(urls, url)
+	}
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	recorder := NewRecorder(NewFetcher(FetcherOptions{}), dir)
+	recorded := map[string]fetchResult{}
+	for _, url := range urls {
+		feed, err := recorder.Fetch(ctx, url)
+		recorded[url] = fetchResult{feed, err}
+	}
+
+	if recorded[urls[0]].err != nil {
+		t.Fatalf("recording %s: %v", urls[0], recorded[urls[0]].err)
+	}
+	var statusErr *StatusError
+	if err := recorded[urls[1]].err; !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusGone {
+		t.Fatalf("recording %s: got %v, want a 410 StatusError", urls[1], err)
+	}
+	if err := recorded[urls[2]].err; !errors.As(err, &statusErr) || statusErr.RetryAfter != 2*time.Minute {
+		t.Fatalf("recording %s: got %v, want a StatusError with RetryAfter", urls[2], err)
+	}
+	if recorded[urls[3]].err == nil {
+		t.Fatalf("recording %s: want a transport error", urls[3])
+	}
+	for url, permanentURL := range redirects {
+		if got := recorded[url]; got.err != nil || got.feed.PermanentURL != permanentURL {
+			t.Fatalf("recording %s: got %v, permanent URL %q, want %q", url, got.err, got.feed.PermanentURL, permanentURL)
+		}
+	}
+
+	// Replaying must not need the server.
+	server.Close()
+	replayer := NewReplayer(dir)
+	for _, url := range urls {
+		want := recorded[url]
+		feed, err := replayer.Fetch(ctx, url)
+		if !reflect.DeepEqual(feed, want.feed) {
+			t.Errorf("replaying %s: got feed %+v, want %+v", url, feed, want.feed)
+		}
+		switch {
+		case want.err == nil:
+			if err != nil {
+				t.Errorf("replaying %s: %v", url, err)
+			}
+		case err == nil:
+			t.Errorf("replaying %s: got no error, want %v", url, want.err)
+		default:
+			if err.Error() != want.err.Error() {
+				t.Errorf("replaying %s: got error %q, want %q", url, err, want.err)
+			}
+			var wantStatus, gotStatus *StatusError
+			if errors.As(want.err, &wantStatus) {
+				if !errors.As(err, &gotStatus) || *gotStatus != *wantStatus {
+					t.Errorf("replaying %s: got %#v, want %#v", url, err, wantStatus)
+				}
+			}
+		}
+	}
+
+	_, err := replayer.Fetch(ctx, server.URL+"/unknown")
+	if !errors.Is(err, ErrNotRecorded) {
+		t.Errorf("replaying an unrecorded URL: got %v, want %v", err, ErrNotRecorded)
+	}
+}
@@ -0,0 +1,135 @@
+package rss
+
+import (
+	"encoding/xml"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Link is a link element. Plain RSS links carry the URL as text, while
+// atom:link elements use the href and rel attributes.
+type Link struct {
+	XMLName xml.Name
+	Rel     string `xml:"rel,attr"`
+	Href    string `xml:"href,attr"`
+	Type    string `xml:"type,attr"`
+	Length  string `xml:"length,attr"`
+	Value   string `xml:",chardata"`
+}
+
+func plainLink(links []Link) string {
+	for _, link := range links {
+		if link.XMLName.Space == "" {
+			return strings.TrimSpace(link.Value)
+		}
+	}
+	return ""
+}
+
+// urlAttributes lists the HTML attributes rewritten by resolveHTML.
+var urlAttributes = map[string]bool{
+	"href":   true,
+	"src":    true,
+	"poster": true,
+}
+
+// ResolveURLs makes item links, enclosure and artwork URLs and the href/src
+// attributes inside item descriptions absolute. Relative references are
+// resolved against xml:base where present, otherwise against the channel
+// link, and finally against feedURL itself.
+func (f *Feed) ResolveURLs(feedURL string) {
+	base, err := url.Parse(feedURL)
+	if err != nil {
+		base = &url.URL{}
+	}
+	base = resolveBase(base, f.Base)
+
+	channel := &f.Channel
+	if channel.Base != "" {
+		base = resolveBase(base, channel.Base)
+	} else if channel.Link != "" {
+		base = resolveBase(base, channel.Link)
+	}
+
+	channel.Link = resolveReference(base, channel.Link)
+	channel.Image.Href = resolveReference(base, channel.Image.Href)
+
+	for i := range channel.Items {
+		item := &channel.Items[i]
+		itemBase := resolveBase(base, item.Base)
+
+		item.Link = resolveReference(itemBase, item.Link)
+		item.Image.Href = resolveReference(itemBase, item.Image.Href)
+		for j := range item.Enclosures {
+			item.Enclosures[j].URL = resolveReference(itemBase, item.Enclosures[j].URL)
+		}
+		item.Description = resolveHTML(itemBase, item.Description)
+	}
+}
+
+func resolveBase(base *url.URL, ref string) *url.URL {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return base
+	}
+	parsed, err := url.Parse(ref)
+	if err != nil {
+		return base
+	}
+	return base.ResolveReference(parsed)
+}
+
+func resolveReference(base *url.URL, ref string) string {
+	trimmed := strings.TrimSpace(ref)
+	if trimmed == "" || base.Scheme == "" {
+		return ref
+	}
+	parsed, err := url.Parse(trimmed)
+	if err != nil || parsed.IsAbs() {
+		return ref
+	}
+	return base.ResolveReference(parsed).String()
+}
+
+// resolveHTML rewrites relative URL attributes in an HTML fragment, leaving
+// the rest of the markup byte for byte as it was.
+func resolveHTML(base *url.URL, fragment string) string {
+	if base.Scheme == "" || !strings.Contains(fragment, "<") {
+		return fragment
+	}
+
+	var out strings.Builder
+	tokenizer := html.NewTokenizer(strings.NewReader(fragment))
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			break
+		}
+		if tokenType != html.StartTagToken && tokenType != html.SelfClosingTagToken {
+			out.Write(tokenizer.Raw())
+			continue
+		}
+
+		// Token may overwrite the buffer behind Raw, so keep a copy.
+		raw := append([]byte(nil), tokenizer.Raw()...)
+		token := tokenizer.Token()
+		changed := false
+		for i, attr := range token.Attr {
+			if attr.Namespace != "" || !urlAttributes[attr.Key] {
+				continue
+			}
+			if resolved := resolveReference(base, attr.Val); resolved != attr.Val {
+				token.Attr[i].Val = resolved
+				changed = true
+			}
+		}
+		if changed {
+			out.WriteString(token.String())
+		} else {
+			out.Write(raw)
+		}
+	}
+	return out.String()
+}
@@ -0,0 +1,109 @@
+package rss
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestResolveURLs(t *testing.T) {
+	tests := []struct {
+		name     string
+		feed     string
+		feedURL  string
+		wantLink string
+		wantEnc  string
+		wantDesc string
+	}{
+		{
+			name:     "against the channel link",
+			feed:     `<rss><channel><link>https://blog.example/news/</link><item><link>post.html</link><enclosure url="/media/1.mp3"/><description>&lt;img src="a.png"&gt;</description></item></channel></rss>`,
+			feedURL:  "https://feeds.example/rss",
+			wantLink: "https://blog.example/news/post.html",
+			wantEnc:  "https://blog.example/media/1.mp3",
+			wantDesc: `<img src="https://blog.example/news/a.png">`,
+		},
+		{
+			name:     "against the feed URL without a channel link",
+			feed:     `<rss><channel><item><link>post.html</link></item></channel></rss>`,
+			feedURL:  "https://feeds.example/a/rss.xml",
+			wantLink: "https://feeds.example/a/post.html",
+		},
+		{
+			name:     "relative channel link",
+			feed:     `<rss><channel><link>/blog/</link><item><link>post.html</link></item></channel></rss>`,
+			feedURL:  "https://example.com/feed.xml",
+			wantLink: "https://example.com/blog/post.html",
+		},
+		{
+			name:     "item xml:base",
+			feed:     `<rss><channel><link>https://example.com/</link><item xml:base="/2024/"><link>post.html</link><enclosure url="ep.mp3"/></item></channel></rss>`,
+			feedURL:  "https://example.com/feed.xml",
+			wantLink: "https://example.com/2024/post.html",
+			wantEnc:  "https://example.com/2024/ep.mp3",
+		},
+		{
+			name:     "channel xml:base beats the channel link",
+			feed:     `<rss><channel xml:base="https://cdn.example/"><link>https://example.com/</link><item><link>post.html</link></item></channel></rss>`,
+			feedURL:  "https://example.com/feed.xml",
+			wantLink: "https://cdn.example/post.html",
+		},
+		{
+			name:     "absolute URLs are kept",
+			feed:     `<rss><channel><link>https://example.com/</link><item><link>https://other.example/x</link><enclosure url="https://media.example/1.mp3"/></item></channel></rss>`,
+			feedURL:  "https://example.com/feed.xml",
+			wantLink: "https://other.example/x",
+			wantEnc:  "https://media.example/1.mp3",
+		},
+		{
+			name:     "no absolute base leaves references alone",
+			feed:     `<rss><channel><item><link>post.html</link></item></channel></rss>`,
+			feedURL:  "",
+			wantLink: "post.html",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed, err := Parse([]byte(tt.feed), "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			feed.ResolveURLs(tt.feedURL)
+
+			item := feed.Channel.Items[0]
+			if item.Link != tt.wantLink {
+				t.Errorf("link = %q, want %q", item.Link, tt.wantLink)
+			}
+			if tt.wantEnc != "" && item.Enclosures[0].URL != tt.wantEnc {
+				t.Errorf("enclosure = %q, want %q", item.Enclosures[0].URL, tt.wantEnc)
+			}
+			if tt.wantDesc != "" && item.Description != tt.wantDesc {
+				t.Errorf("description = %q, want %q", item.Description, tt.wantDesc)
+			}
+		})
+	}
+}
+
+func TestResolveHTML(t *testing.T) {
+	base, _ := url.Parse("https://example.com/posts/1/")
+	tests := []struct {
+		name     string
+		fragment string
+		want     string
+	}{
+		{"plain text", "no markup here", "no markup here"},
+		{"link", `<a href="../2/">next</a>`, `<a href="https://example.com/posts/2/">next</a>`},
+		{"image", `<img src="/img/a.png" alt="A">`, `<img src="https://example.com/img/a.png" alt="A">`},
+		{"video poster", `<video poster="p.jpg"></video>`, `<video poster="https://example.com/posts/1/p.jpg"></video>`},
+		{"absolute kept byte for byte", `<a  href='https://x.example/'>x</a>`, `<a  href='https://x.example/'>x</a>`},
+		{"other attributes untouched", `<p class="note" data-src="x.png">hi</p>`, `<p class="note" data-src="x.png">hi</p>`},
+		{"fragment and mailto", `<a href="#top">top</a><a href="mailto:a@b.example">mail</a>`, `<a href="https://example.com/posts/1/#top">top</a><a href="mailto:a@b.example">mail</a>`},
+		{"text between tags kept", `<p>a &amp; b <a href="c">c</a></p>`, `<p>a &amp; b <a href="https://example.com/posts/1/c">c</a></p>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolveHTML(base, tt.fragment); got != tt.want {
+				t.Errorf("resolveHTML(%q) = %q, want %q", tt.fragment, got, tt.want)
+			}
+		})
+	}
+}
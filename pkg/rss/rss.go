@@ -1,54 +1,138 @@
 package rss
 
 import (
-	"context"
+	"bytes"
 	"encoding/xml"
+	"errors"
+	"fmt"
 	"html"
 	"io"
-	"net/http"
 )
 
+// Feed formats understood by Parse.
+const (
+	FormatRSS  = "RSS"
+	FormatAtom = "Atom"
+	FormatJSON = "JSON Feed"
+)
+
+// NotAFeedError is returned by Parse for documents that are well formed but
+// are not a feed in any supported format, such as HTML pages.
+type NotAFeedError struct {
+	Reason string
+}
+
+func (e *NotAFeedError) Error() string {
+	return "not a feed: " + e.Reason
+}
+
+// Feed is a parsed feed. Atom and JSON Feed documents are converted into
+// the same RSS-shaped structure.
 type Feed struct {
+	// Format is the format the feed was published in.
+	Format string `xml:"-"`
+	// PermanentURL is set when the feed was reached through permanent
+	// (301 or 308) redirects only, and holds the URL it now lives at.
+	PermanentURL string `xml:"-"`
+
+	Base    string `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
 	Channel struct {
-		Title       string  `xml:"title"`
-		Link        string  `xml:"link"`
-		Description string  `xml:"description"`
-		Items       []Item  `xml:"item"`
+		Base        string      `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
+		Title       string      `xml:"title"`
+		Link        string      `xml:"-"`
+		Links       []Link      `xml:"link"`
+		Description string      `xml:"description"`
+		Image       ITunesImage `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+		TTL         string      `xml:"ttl"`
+		SkipHours   []string    `xml:"skipHours>hour"`
+		SkipDays    []string    `xml:"skipDays>day"`
+
+		UpdatePeriod    string `xml:"http://purl.org/rss/1.0/modules/syndication/ updatePeriod"`
+		UpdateFrequency string `xml:"http://purl.org/rss/1.0/modules/syndication/ updateFrequency"`
+
+		Items []Item `xml:"item"`
 	} `xml:"channel"`
 }
 
 type Item struct {
-	Title       string `xml:"title"`
-	Link        string `xml:"link"`
-	Description string `xml:"description"`
-	PubDate     string `xml:"pubDate"`
+	Base        string      `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
+	Title       string      `xml:"title"`
+	Link        string      `xml:"-"`
+	Links       []Link      `xml:"link"`
+	Description string      `xml:"description"`
+	PubDate     string      `xml:"pubDate"`
+	Authors     []Element   `xml:"author"`
+	Creators    []string    `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Categories  []string    `xml:"category"`
+	Enclosures  []Enclosure `xml:"enclosure"`
+	Duration    string      `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+	Episode     string      `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd episode"`
+	Season      string      `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd season"`
+	Image       ITunesImage `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
 }
 
-func FetchFeed(ctx context.Context, feedURL string) (*Feed, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
-	if err != nil {
-		return nil, err
+// Parse decodes a feed document in any of the supported formats.
+// contentType is the value of the Content-Type header it was served with,
+// if any, and is used to determine the character encoding.
+func Parse(data []byte, contentType string) (*Feed, error) {
+	trimmed := bytes.TrimLeft(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")), " \t\r\n")
+	if len(trimmed) == 0 {
+		return nil, &NotAFeedError{Reason: "document is empty"}
+	}
+	if trimmed[0] == '{' {
+		return parseJSONFeed(trimmed)
 	}
 
-	client := http.Client{}
-	res, err := client.Do(req)
+	decoder, err := newXMLDecoder(data, contentType)
 	if err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
-
-	data, err := io.ReadAll(res.Body)
+	root, err := rootElement(decoder)
 	if err != nil {
 		return nil, err
 	}
 
-	var rssFeed Feed
-	if err := xml.Unmarshal(data, &rssFeed); err != nil {
-		return nil, err
+	switch {
+	case root.Name.Local == "rss":
+		var rssFeed Feed
+		if err := decoder.DecodeElement(&rssFeed, &root); err != nil {
+			return nil, err
+		}
+		rssFeed.Format = FormatRSS
+		selectLinks(&rssFeed)
+		unescapeStrings(&rssFeed)
+		return &rssFeed, nil
+	case root.Name.Local == "feed" && root.Name.Space == atomNamespace:
+		return parseAtom(decoder, &root)
+	default:
+		return nil, &NotAFeedError{
+			Reason: fmt.Sprintf("document root is <%s>, expected <rss>, an Atom <feed> or a JSON Feed", root.Name.Local),
+		}
+	}
+}
+
+func rootElement(decoder *xml.Decoder) (xml.StartElement, error) {
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return xml.StartElement{}, &NotAFeedError{Reason: "document has no root element"}
+		}
+		if err != nil {
+			return xml.StartElement{}, &NotAFeedError{Reason: "document is not valid XML: " + err.Error()}
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start, nil
+		}
 	}
+}
 
-	unescapeStrings(&rssFeed)
-	return &rssFeed, nil
+// selectLinks fills in the plain RSS link of the channel and its items,
+// ignoring link elements from other namespaces such as atom:link.
+func selectLinks(feed *Feed) {
+	feed.Channel.Link = plainLink(feed.Channel.Links)
+	for i := range feed.Channel.Items {
+		feed.Channel.Items[i].Link = plainLink(feed.Channel.Items[i].Links)
+	}
 }
 
 func unescapeStrings(feed *Feed) {
@@ -0,0 +1,90 @@
+package rss
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+var updatePeriods = map[string]time.Duration{
+	"hourly":  time.Hour,
+	"daily":   24 * time.Hour,
+	"weekly":  7 * 24 * time.Hour,
+	"monthly": 30 * 24 * time.Hour,
+	"yearly":  365 * 24 * time.Hour,
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// RefreshInterval returns the minimum time the publisher asks readers to
+// wait between fetches, taken from <ttl> and the syndication module's
+// updatePeriod/updateFrequency. The larger of the two wins; ok is false
+// when the feed gives no hint.
+func (f *Feed) RefreshInterval() (time.Duration, bool) {
+	var interval time.Duration
+
+	if minutes, ok := parsePositiveInt(f.Channel.TTL); ok {
+		interval = time.Duration(minutes) * time.Minute
+	}
+
+	if period, ok := updatePeriods[strings.ToLower(strings.TrimSpace(f.Channel.UpdatePeriod))]; ok {
+		frequency, ok := parsePositiveInt(f.Channel.UpdateFrequency)
+		if !ok {
+			frequency = 1
+		}
+		interval = max(interval, period/time.Duration(frequency))
+	}
+
+	return interval, interval > 0
+}
+
+// SkipHoursMask returns <skipHours> as a bit mask in which bit n is set when
+// fetching should be skipped during hour n (0-23, GMT).
+func (f *Feed) SkipHoursMask() int32 {
+	var mask int32
+	for _, value := range f.Channel.SkipHours {
+		hour, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || hour < 0 || hour > 24 {
+			continue
+		}
+		// Some publishers number hours 1-24; treat 24 as midnight.
+		mask |= 1 << (hour % 24)
+	}
+	if mask == 1<<24-1 {
+		// A feed that may never be fetched is a publisher mistake.
+		return 0
+	}
+	return mask
+}
+
+// SkipDaysMask returns <skipDays> as a bit mask indexed by time.Weekday.
+func (f *Feed) SkipDaysMask() int32 {
+	var mask int32
+	for _, value := range f.Channel.SkipDays {
+		if day, ok := weekdays[strings.ToLower(strings.TrimSpace(value))]; ok {
+			mask |= 1 << day
+		}
+	}
+	if mask == 1<<7-1 {
+		return 0
+	}
+	return mask
+}
+
+// HourBit and DayBit return the bits of SkipHoursMask and SkipDaysMask
+// that correspond to t.
+func HourBit(t time.Time) int32 {
+	return 1 << t.UTC().Hour()
+}
+
+func DayBit(t time.Time) int32 {
+	return 1 << t.UTC().Weekday()
+}
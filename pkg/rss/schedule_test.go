@@ -0,0 +1,106 @@
+package rss
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func parseChannel(t *testing.T, channel string) *Feed {
+	t.Helper()
+	feed, err := Parse([]byte(`<rss xmlns:sy="http://purl.org/rss/1.0/modules/syndication/"><channel>`+channel+`</channel></rss>`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return feed
+}
+
+func TestRefreshInterval(t *testing.T) {
+	tests := []struct {
+		name    string
+		channel string
+		want    time.Duration
+		wantOK  bool
+	}{
+		{"no hints", "", 0, false},
+		{"ttl", "<ttl>60</ttl>", time.Hour, true},
+		{"ttl with spaces", "<ttl> 15 </ttl>", 15 * time.Minute, true},
+		{"zero ttl", "<ttl>0</ttl>", 0, false},
+		{"negative ttl", "<ttl>-5</ttl>", 0, false},
+		{"bad ttl", "<ttl>soon</ttl>", 0, false},
+		{"update period", "<sy:updatePeriod>daily</sy:updatePeriod>", 24 * time.Hour, true},
+		{"update period and frequency", "<sy:updatePeriod>hourly</sy:updatePeriod><sy:updateFrequency>4</sy:updateFrequency>", 15 * time.Minute, true},
+		{"update period in capitals", "<sy:updatePeriod>Weekly</sy:updatePeriod>", 7 * 24 * time.Hour, true},
+		{"bad frequency counts as one", "<sy:updatePeriod>daily</sy:updatePeriod><sy:updateFrequency>0</sy:updateFrequency>", 24 * time.Hour, true},
+		{"unknown period", "<sy:updatePeriod>fortnightly</sy:updatePeriod>", 0, false},
+		{"larger of ttl and period", "<ttl>120</ttl><sy:updatePeriod>hourly</sy:updatePeriod>", 2 * time.Hour, true},
+		{"larger of period and ttl", "<ttl>10</ttl><sy:updatePeriod>hourly</sy:updatePeriod>", time.Hour, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseChannel(t, tt.channel).RefreshInterval()
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("RefreshInterval() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestSkipHoursMask(t *testing.T) {
+	allHours := ""
+	for hour := range 24 {
+		allHours += "<hour>" + strconv.Itoa(hour) + "</hour>"
+	}
+
+	tests := []struct {
+		name    string
+		channel string
+		want    int32
+	}{
+		{"none", "", 0},
+		{"some hours", "<skipHours><hour>0</hour><hour>13</hour><hour>23</hour></skipHours>", 1<<0 | 1<<13 | 1<<23},
+		{"24 is midnight", "<skipHours><hour>24</hour></skipHours>", 1 << 0},
+		{"out of range and junk ignored", "<skipHours><hour>25</hour><hour>-1</hour><hour>noon</hour><hour> 7 </hour></skipHours>", 1 << 7},
+		{"every hour is ignored", "<skipHours>" + allHours + "</skipHours>", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseChannel(t, tt.channel).SkipHoursMask(); got != tt.want {
+				t.Errorf("SkipHoursMask() = %b, want %b", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSkipDaysMask(t *testing.T) {
+	tests := []struct {
+		name    string
+		channel string
+		want    int32
+	}{
+		{"none", "", 0},
+		{"weekend", "<skipDays><day>Saturday</day><day>Sunday</day></skipDays>", 1<<time.Saturday | 1<<time.Sunday},
+		{"case and spaces", "<skipDays><day> monday </day><day>FRIDAY</day></skipDays>", 1<<time.Monday | 1<<time.Friday},
+		{"junk ignored", "<skipDays><day>Caturday</day><day>Tuesday</day></skipDays>", 1 << time.Tuesday},
+		{"every day is ignored", "<skipDays><day>Monday</day><day>Tuesday</day><day>Wednesday</day><day>Thursday</day><day>Friday</day><day>Saturday</day><day>Sunday</day></skipDays>", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseChannel(t, tt.channel).SkipDaysMask(); got != tt.want {
+				t.Errorf("SkipDaysMask() = %b, want %b", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHourAndDayBits(t *testing.T) {
+	// 22:30 on a Sunday in New York is 03:30 on Monday in GMT.
+	newYork := time.FixedZone("EST", -5*60*60)
+	at := time.Date(2024, time.January, 7, 22, 30, 0, 0, newYork)
+	if got := HourBit(at); got != 1<<3 {
+		t.Errorf("HourBit(%v) = %b, want bit 3", at, got)
+	}
+	if got := DayBit(at); got != 1<<time.Monday {
+		t.Errorf("DayBit(%v) = %b, want Monday", at, got)
+	}
+}
@@ -0,0 +1,141 @@
+package websub
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnknownSubscription is returned by a Subscriber for callback IDs it
+// does not recognise.
+var ErrUnknownSubscription = errors.New("unknown subscription")
+
+// maxContentSize bounds the body of a content distribution request.
+const maxContentSize = 10 << 20
+
+// Subscription is what the callback handler needs to know about a
+// subscription to validate requests for it.
+type Subscription struct {
+	Topic  string
+	Secret string
+}
+
+// Subscriber connects the callback handler to subscription storage.
+type Subscriber interface {
+	// Subscription returns the subscription a callback ID belongs to.
+	Subscription(ctx context.Context, id string) (*Subscription, error)
+	// Verified is called once a hub has confirmed a subscribe or
+	// unsubscribe request, or reported that it denied one.
+	Verified(ctx context.Context, id, mode string, lease time.Duration) error
+	// Deliver receives authenticated content pushed by the hub.
+	Deliver(ctx context.Context, id, contentType string, body []byte) error
+}
+
+// CallbackHandler serves hub callbacks. The last path segment of the
+// request URL identifies the subscription.
+type CallbackHandler struct {
+	Subscriber Subscriber
+}
+
+func (h *CallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+
+	sub, err := h.Subscriber.Subscription(r.Context(), id)
+	if errors.Is(err, ErrUnknownSubscription) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Printf("websub: looking up subscription %s: %v\n", id, err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.verify(w, r, id, sub)
+	case http.MethodPost:
+		h.deliver(w, r, id, sub)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// verify answers a hub's verification of intent by echoing the challenge
+// when the request matches the subscription.
+func (h *CallbackHandler) verify(w http.ResponseWriter, r *http.Request, id string, sub *Subscription) {
+	query := r.URL.Query()
+	mode := query.Get("hub.mode")
+
+	if query.Get("hub.topic") != sub.Topic {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch mode {
+	case ModeDenied:
+		if err := h.Subscriber.Verified(r.Context(), id, mode, 0); err != nil {
+			log.Printf("websub: recording denial of %s: %v\n", id, err)
+		}
+		w.WriteHeader(http.StatusOK)
+		return
+	case ModeSubscribe, ModeUnsubscribe:
+	default:
+		http.Error(w, "unsupported hub.mode", http.StatusBadRequest)
+		return
+	}
+
+	challenge := query.Get("hub.challenge")
+	if challenge == "" {
+		http.Error(w, "missing hub.challenge", http.StatusBadRequest)
+		return
+	}
+
+	var lease time.Duration
+	if seconds, err := strconv.Atoi(query.Get("hub.lease_seconds")); err == nil && seconds > 0 {
+		lease = time.Duration(seconds) * time.Second
+	}
+
+	if err := h.Subscriber.Verified(r.Context(), id, mode, lease); err != nil {
+		log.Printf("websub: refusing %s of %s: %v\n", mode, id, err)
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, challenge)
+}
+
+// deliver accepts pushed content. Content with a missing or invalid
+// signature is acknowledged but dropped, as the specification requires.
+func (h *CallbackHandler) deliver(w http.ResponseWriter, r *http.Request, id string, sub *Subscription) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxContentSize+1))
+	if err != nil {
+		http.Error(w, "error reading body", http.StatusBadRequest)
+		return
+	}
+	if len(body) > maxContentSize {
+		http.Error(w, "content too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if sub.Secret != "" && !VerifySignature(sub.Secret, body, r.Header.Get(SignatureHeader)) {
+		log.Printf("websub: dropping content for %s with invalid signature\n", id)
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	if err := h.Subscriber.Deliver(r.Context(), id, r.Header.Get("Content-Type"), body); err != nil {
+		log.Printf("websub: processing content for %s: %v\n", id, err)
+		http.Error(w, "error processing content", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,108 @@
+package websub
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ModeSubscribe   = "subscribe"
+	ModeUnsubscribe = "unsubscribe"
+	ModeDenied      = "denied"
+)
+
+// SignatureHeader carries the HMAC of pushed content.
+const SignatureHeader = "X-Hub-Signature"
+
+// Request asks a hub to start or stop delivering a topic to a callback.
+type Request struct {
+	Hub      string
+	Topic    string
+	Callback string
+	Secret   string
+	Lease    time.Duration
+}
+
+// Send posts a subscription request with the given mode to the hub. Hubs
+// answer 202 Accepted and verify the request asynchronously.
+func (r *Request) Send(ctx context.Context, client *http.Client, mode string) error {
+	form := url.Values{
+		"hub.mode":     {mode},
+		"hub.topic":    {r.Topic},
+		"hub.callback": {r.Callback},
+	}
+	if r.Secret != "" {
+		form.Set("hub.secret", r.Secret)
+	}
+	if r.Lease > 0 {
+		form.Set("hub.lease_seconds", strconv.Itoa(int(r.Lease/time.Second)))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.Hub, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return fmt.Errorf("hub %s rejected %s request: %s %s", r.Hub, mode, res.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// VerifySignature checks an X-Hub-Signature header of the form
+// "method=hexdigest" against the HMAC of body keyed with secret.
+func VerifySignature(secret string, body []byte, header string) bool {
+	method, digest, ok := strings.Cut(strings.TrimSpace(header), "=")
+	if !ok {
+		return false
+	}
+
+	var newHash func() hash.Hash
+	switch strings.ToLower(method) {
+	case "sha1":
+		newHash = sha1.New
+	case "sha256":
+		newHash = sha256.New
+	case "sha384":
+		newHash = sha512.New384
+	case "sha512":
+		newHash = sha512.New
+	default:
+		return false
+	}
+
+	expected, err := hex.DecodeString(digest)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// Sign returns an X-Hub-Signature value for body, as a hub would send it.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,195 @@
+package websub
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSend(t *testing.T) {
+	var got url.Values
+	hub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/x-www-form-urlencoded" {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+		}
+		got = r.PostForm
+		if got.Get("hub.topic") == "https://example.com/rejected" {
+			http.Error(w, "no such topic", http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer hub.Close()
+
+	req := Request{
+		Hub:      hub.URL,
+		Topic:    "https://example.com/feed",
+		Callback: "https://gator.example/websub/1",
+		Secret:   "s3cret",
+		Lease:    time.Hour,
+	}
+	if err := req.Send(context.Background(), hub.Client(), ModeSubscribe); err != nil {
+		t.Fatal(err)
+	}
+	want := url.Values{
+		"hub.mode":          {"subscribe"},
+		"hub.topic":         {"https://example.com/feed"},
+		"hub.callback":      {"https://gator.example/websub/1"},
+		"hub.secret":        {"s3cret"},
+		"hub.lease_seconds": {"3600"},
+	}
+	for key := range want {
+		if got.Get(key) != want.Get(key) {
+			t.Errorf("%s = %q, want %q", key, got.Get(key), want.Get(key))
+		}
+	}
+
+	req.Topic = "https://example.com/rejected"
+	err := req.Send(context.Background(), hub.Client(), ModeSubscribe)
+	if err == nil || !strings.Contains(err.Error(), "no such topic") {
+		t.Errorf("got %v, want the hub's rejection", err)
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte("<rss/>")
+	tests := []struct {
+		name   string
+		header string
+		want   bool
+	}{
+		{"valid", Sign("secret", body), true},
+		{"wrong digest", "sha1=2aa4e1fe0d0b0ff6c5ad0d0e1c43a9b4a4d5a5a9", false},
+		{"wrong secret", Sign("other", body), false},
+		{"unknown method", "md5=00", false},
+		{"not hex", "sha256=zz", false},
+		{"missing", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifySignature("secret", body, tt.header); got != tt.want {
+				t.Errorf("VerifySignature(%q) = %v, want %v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+type verification struct {
+	id, mode string
+	lease    time.Duration
+}
+
+type delivery struct {
+	id, contentType, body string
+}
+
+type fakeSubscriber struct {
+	subs      map[string]*Subscription
+	verified  []verification
+	delivered []delivery
+}
+
+func (f *fakeSubscriber) Subscription(ctx context.Context, id string) (*Subscription, error) {
+	sub, ok := f.subs[id]
+	if !ok {
+		return nil, ErrUnknownSubscription
+	}
+	return sub, nil
+}
+
+func (f *fakeSubscriber) Verified(ctx context.Context, id, mode string, lease time.Duration) error {
+	f.verified = append(f.verified, verification{id, mode, lease})
+	return nil
+}
+
+func (f *fakeSubscriber) Deliver(ctx context.Context, id, contentType string, body []byte) error {
+	f.delivered = append(f.delivered, delivery{id, contentType, string(body)})
+	return nil
+}
+
+func TestCallbackHandler(t *testing.T) {
+	subscriber := &fakeSubscriber{subs: map[string]*Subscription{
+		"1": {Topic: "https://example.com/feed", Secret: "secret"},
+	}}
+	server := httptest.NewServer(&CallbackHandler{Subscriber: subscriber})
+	defer server.Close()
+
+	get := func(path string, query url.Values) (*http.Response, string) {
+		t.Helper()
+		res, err := http.Get(server.URL + path + "?" + query.Encode())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		body, _ := io.ReadAll(res.Body)
+		return res, string(body)
+	}
+	post := func(path, signature, body string) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodPost, server.URL+path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/rss+xml")
+		if signature != "" {
+			req.Header.Set(SignatureHeader, signature)
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		return res
+	}
+
+	res, body := get("/websub/1", url.Values{
+		"hub.mode":          {ModeSubscribe},
+		"hub.topic":         {"https://example.com/feed"},
+		"hub.challenge":     {"abc123"},
+		"hub.lease_seconds": {"600"},
+	})
+	if res.StatusCode != http.StatusOK || body != "abc123" {
+		t.Errorf("verification answered %s %q, want the challenge echoed", res.Status, body)
+	}
+	if len(subscriber.verified) != 1 || subscriber.verified[0] != (verification{"1", ModeSubscribe, 10 * time.Minute}) {
+		t.Errorf("verified = %+v", subscriber.verified)
+	}
+
+	res, body = get("/websub/1", url.Values{
+		"hub.mode":      {ModeSubscribe},
+		"hub.topic":     {"https://example.com/other"},
+		"hub.challenge": {"abc123"},
+	})
+	if res.StatusCode != http.StatusNotFound || body == "abc123" {
+		t.Errorf("verification for another topic answered %s %q, want 404", res.Status, body)
+	}
+
+	res, _ = get("/websub/2", url.Values{"hub.mode": {ModeSubscribe}})
+	if res.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown subscription answered %s, want 404", res.Status)
+	}
+
+	content := "<rss><channel></channel></rss>"
+	if res := post("/websub/1", Sign("wrong", []byte(content)), content); res.StatusCode != http.StatusAccepted {
+		t.Errorf("badly signed delivery answered %s, want 202", res.Status)
+	}
+	if res := post("/websub/1", "", content); res.StatusCode != http.StatusAccepted {
+		t.Errorf("unsigned delivery answered %s, want 202", res.Status)
+	}
+	if len(subscriber.delivered) != 0 {
+		t.Fatalf("unauthenticated content was delivered: %+v", subscriber.delivered)
+	}
+
+	if res := post("/websub/1", Sign("secret", []byte(content)), content); res.StatusCode != http.StatusAccepted {
+		t.Errorf("signed delivery answered %s, want 202", res.Status)
+	}
+	want := delivery{"1", "application/rss+xml", content}
+	if len(subscriber.delivered) != 1 || subscriber.delivered[0] != want {
+		t.Errorf("delivered = %+v, want %+v", subscriber.delivered, want)
+	}
+}
@@ -0,0 +1,139 @@
+-- name: ListAllFeeds :many
+SELECT * FROM feeds
+ORDER BY id;
+
+-- name: ListAllFeedFollows :many
+SELECT * FROM feed_follows
+ORDER BY id;
+
+-- name: ListAllPosts :many
+SELECT * FROM posts
+ORDER BY id;
+
+-- name: ListAllCategories :many
+SELECT * FROM categories
+ORDER BY id;
+
+-- name: ListAllPostCategories :many
+SELECT * FROM post_categories
+ORDER BY post_id, category_id;
+
+-- name: ListAllPostEnclosures :many
+SELECT * FROM post_enclosures
+ORDER BY id;
+
+-- name: ListAllDownloads :many
+SELECT * FROM downloads
+ORDER BY id;
+
+
+-- name: RestoreUser :exec
+INSERT INTO users (id, created_at, updated_at, name, password_hash, role, deactivated_at)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+);
+
+-- name: RestoreFeed :exec
+INSERT INTO feeds (id, name, url, user_id, created_at, updated_at, last_fetched_at, disabled_at, disabled_reason, next_fetch_at, min_refresh_seconds, skip_hours, skip_days)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    $11,
+    $12,
+    $13
+);
+
+-- name: RestoreFeedFollow :exec
+INSERT INTO feed_follows (id, created_at, updated_at, user_id, feed_id)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+);
+
+-- name: RestorePost :exec
+INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id, author)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9
+);
+
+-- name: RestoreCategory :exec
+INSERT INTO categories (id, name)
+VALUES (
+    $1,
+    $2
+);
+
+-- name: RestorePostCategory :exec
+INSERT INTO post_categories (post_id, category_id)
+VALUES (
+    $1,
+    $2
+);
+
+-- name: RestorePostEnclosure :exec
+INSERT INTO post_enclosures (id, created_at, post_id, url, length, mime_type, duration_seconds, episode, season, image_url)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10
+);
+
+-- name: RestoreDownload :exec
+INSERT INTO downloads (id, created_at, updated_at, user_id, enclosure_id, path, size, sha256)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8
+);
+
+-- name: SyncSequences :exec
+SELECT
+    setval(pg_get_serial_sequence('feeds', 'id'), COALESCE((SELECT MAX(id) FROM feeds), 0) + 1, false),
+    setval(pg_get_serial_sequence('feed_follows', 'id'), COALESCE((SELECT MAX(id) FROM feed_follows), 0) + 1, false),
+    setval(pg_get_serial_sequence('posts', 'id'), COALESCE((SELECT MAX(id) FROM posts), 0) + 1, false),
+    setval(pg_get_serial_sequence('categories', 'id'), COALESCE((SELECT MAX(id) FROM categories), 0) + 1, false),
+    setval(pg_get_serial_sequence('post_enclosures', 'id'), COALESCE((SELECT MAX(id) FROM post_enclosures), 0) + 1, false),
+    setval(pg_get_serial_sequence('downloads', 'id'), COALESCE((SELECT MAX(id) FROM downloads), 0) + 1, false);
+
+-- name: DatabaseHasData :one
+SELECT EXISTS (SELECT 1 FROM users)
+    OR EXISTS (SELECT 1 FROM feeds)
+    OR EXISTS (SELECT 1 FROM posts)
+    OR EXISTS (SELECT 1 FROM categories) AS has_data;
@@ -0,0 +1,37 @@
+-- name: GetEnclosuresForPostUrl :many
+SELECT pe.*, f.name AS feed_name
+FROM post_enclosures pe
+JOIN posts p ON pe.post_id = p.id
+JOIN feeds f ON p.feed_id = f.id
+WHERE p.url = $1
+ORDER BY pe.id;
+
+-- name: GetNewEnclosuresForUser :many
+SELECT pe.*, f.name AS feed_name
+FROM post_enclosures pe
+JOIN posts p ON pe.post_id = p.id
+JOIN feeds f ON p.feed_id = f.id
+JOIN feed_follows ff ON f.id = ff.feed_id
+WHERE ff.user_id = $1
+  AND NOT EXISTS (
+    SELECT 1 FROM downloads d
+    WHERE d.enclosure_id = pe.id
+      AND d.user_id = ff.user_id
+  )
+ORDER BY p.published_at DESC
+LIMIT $2;
+
+-- name: GetDownload :one
+SELECT *
+FROM downloads
+WHERE user_id = $1 AND enclosure_id = $2;
+
+-- name: UpsertDownload :one
+INSERT INTO downloads (user_id, enclosure_id, path, size, sha256)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (user_id, enclosure_id) DO UPDATE
+SET path = EXCLUDED.path,
+    size = EXCLUDED.size,
+    sha256 = EXCLUDED.sha256,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
@@ -32,10 +32,97 @@ SET last_fetched_at = CURRENT_TIMESTAMP,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1;
 
--- name: GetNextFeedToFetch :one
+-- name: GetNextFeedsToFetch :many
 SELECT *
 FROM feeds
-ORDER BY last_fetched_at NULLS FIRST
-LIMIT 1;
+WHERE disabled_at IS NULL
+  AND (next_fetch_at IS NULL OR next_fetch_at <= CURRENT_TIMESTAMP)
+  AND (last_fetched_at IS NULL
+       OR min_refresh_seconds IS NULL
+       OR last_fetched_at + (min_refresh_seconds * INTERVAL '1 second') <= CURRENT_TIMESTAMP)
+  AND (skip_hours & sqlc.arg('hour_bit')::int) = 0
+  AND (skip_days & sqlc.arg('day_bit')::int) = 0
+ORDER BY next_fetch_at NULLS FIRST, last_fetched_at NULLS FIRST
+LIMIT sqlc.arg('limit');
 
+-- name: UpdateFeedRefreshHints :exec
+UPDATE feeds
+SET min_refresh_seconds = $2,
+    skip_hours = $3,
+    skip_days = $4
+WHERE id = $1;
+
+-- name: PostponeFeedFetch :exec
+UPDATE feeds
+SET next_fetch_at = CURRENT_TIMESTAMP + (sqlc.arg('delay_seconds')::int * INTERVAL '1 second'),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg('id');
+
+-- name: UpdateFeedUrl :one
+UPDATE feeds
+SET url = $2,
+    disabled_at = NULL,
+    disabled_reason = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
+
+-- name: DisableFeed :exec
+UPDATE feeds
+SET disabled_at = CURRENT_TIMESTAMP,
+    disabled_reason = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: MoveFeedFollows :exec
+INSERT INTO feed_follows (created_at, updated_at, user_id, feed_id)
+SELECT created_at, CURRENT_TIMESTAMP, user_id, sqlc.arg('target_feed_id')
+FROM feed_follows
+WHERE feed_id = sqlc.arg('source_feed_id')
+ON CONFLICT (user_id, feed_id) DO NOTHING;
+
+-- name: MoveFeedPosts :exec
+UPDATE posts
+SET feed_id = sqlc.arg('target_feed_id'),
+    updated_at = CURRENT_TIMESTAMP
+WHERE feed_id = sqlc.arg('source_feed_id');
+
+-- name: DeleteFeed :exec
+DELETE FROM feeds
+WHERE id = $1;
+
+
+
+-- name: GetFeed :one
+SELECT *
+FROM feeds
+WHERE id = $1;
+
+-- name: NotifyFeedAdded :exec
+SELECT pg_notify('gator_feed_added', sqlc.arg('feed_id')::text);
+
+-- name: RenameFeed :one
+UPDATE feeds
+SET name = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
+
+-- name: GetFeedStats :one
+SELECT
+    (SELECT COUNT(*) FROM feed_follows WHERE feed_follows.feed_id = $1) AS follower_count,
+    (SELECT COUNT(*) FROM posts WHERE posts.feed_id = $1) AS post_count;
+
+-- name: CountFeedsForUser :one
+SELECT COUNT(*) FROM feeds
+WHERE user_id = $1;
+
+-- name: ReassignFeeds :exec
+UPDATE feeds
+SET user_id = sqlc.arg('to_user_id'),
+    updated_at = CURRENT_TIMESTAMP
+WHERE user_id = sqlc.arg('from_user_id');
 
+-- name: DeleteFeedsForUser :exec
+DELETE FROM feeds
+WHERE user_id = $1;
@@ -0,0 +1,18 @@
+-- name: CreatePostEnclosure :exec
+INSERT INTO post_enclosures (post_id, url, length, mime_type, duration_seconds, episode, season, image_url)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (post_id, url) DO NOTHING;
+
+-- name: GetEpisodesForUser :many
+SELECT
+    pe.*,
+    p.title AS post_title,
+    p.published_at,
+    f.name AS feed_name
+FROM post_enclosures pe
+JOIN posts p ON pe.post_id = p.id
+JOIN feeds f ON p.feed_id = f.id
+JOIN feed_follows ff ON f.id = ff.feed_id
+WHERE ff.user_id = $1
+ORDER BY p.published_at DESC
+LIMIT $2;
@@ -1,6 +1,6 @@
--- name: CreatePost :exec
-INSERT INTO posts (title, url, description, published_at, feed_id)
-VALUES ($1, $2, $3, $4, $5)
+-- name: CreatePost :one
+INSERT INTO posts (title, url, description, published_at, feed_id, author)
+VALUES ($1, $2, $3, $4, $5, $6)
 RETURNING id, created_at, updated_at;
 
 -- name: GetPostsForUser :many
@@ -8,6 +8,22 @@ SELECT p.*, f.name as feed_name
 FROM posts p
 JOIN feeds f ON p.feed_id = f.id
 JOIN feed_follows ff ON f.id = ff.feed_id
-WHERE ff.user_id = $1
+WHERE ff.user_id = sqlc.arg('user_id')
+  AND (sqlc.narg('author')::text IS NULL OR p.author ILIKE '%' || sqlc.narg('author') || '%')
+  AND (sqlc.narg('category')::text IS NULL OR EXISTS (
+    SELECT 1
+    FROM post_categories pc
+    JOIN categories c ON pc.category_id = c.id
+    WHERE pc.post_id = p.id
+      AND c.name = sqlc.narg('category')
+  ))
 ORDER BY p.published_at DESC
+LIMIT sqlc.arg('limit');
+
+-- name: GetRecentPostTimesForFeed :many
+SELECT published_at
+FROM posts
+WHERE feed_id = $1
+  AND published_at IS NOT NULL
+ORDER BY published_at DESC
 LIMIT $2;
@@ -0,0 +1,31 @@
+-- name: CreateSession :exec
+INSERT INTO sessions (token_hash, user_id, expires_at)
+VALUES (
+    sqlc.arg('token_hash'),
+    sqlc.arg('user_id'),
+    CURRENT_TIMESTAMP + (sqlc.arg('lifetime_seconds')::int * INTERVAL '1 second')
+);
+
+-- name: GetUserForSession :one
+SELECT users.*
+FROM sessions
+JOIN users ON users.id = sessions.user_id
+WHERE sessions.token_hash = $1
+  AND sessions.expires_at > CURRENT_TIMESTAMP;
+
+-- name: DeleteSession :exec
+DELETE FROM sessions
+WHERE token_hash = $1;
+
+-- name: DeleteOtherSessionsForUser :exec
+DELETE FROM sessions
+WHERE user_id = $1
+  AND token_hash <> $2;
+
+-- name: DeleteExpiredSessions :exec
+DELETE FROM sessions
+WHERE expires_at <= CURRENT_TIMESTAMP;
+
+-- name: DeleteSessionsForUser :exec
+DELETE FROM sessions
+WHERE user_id = $1;
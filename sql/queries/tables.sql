@@ -1,2 +1,19 @@
 -- name: ResetTables :exec
-TRUNCATE TABLE users, feeds, feed_follows CASCADE;
\ No newline at end of file
+TRUNCATE TABLE users, feeds, feed_follows, categories CASCADE;
+
+-- name: DeleteAllPosts :exec
+DELETE FROM posts;
+
+-- name: DeleteUnusedCategories :exec
+DELETE FROM categories
+WHERE NOT EXISTS (
+    SELECT 1 FROM post_categories WHERE post_categories.category_id = categories.id
+);
+
+-- name: DeleteAllFeedFollowsForUser :exec
+DELETE FROM feed_follows
+WHERE user_id = $1;
+
+-- name: DeleteDownloadsForUser :exec
+DELETE FROM downloads
+WHERE user_id = $1;
\ No newline at end of file
@@ -0,0 +1,41 @@
+-- name: UpsertWebSubSubscription :exec
+INSERT INTO websub_subscriptions (feed_id, hub_url, topic_url, secret)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (feed_id) DO UPDATE
+SET hub_url = EXCLUDED.hub_url,
+    topic_url = EXCLUDED.topic_url,
+    state = 'new',
+    updated_at = CURRENT_TIMESTAMP
+WHERE websub_subscriptions.hub_url <> EXCLUDED.hub_url
+   OR websub_subscriptions.topic_url <> EXCLUDED.topic_url;
+
+-- name: GetWebSubSubscription :one
+SELECT *
+FROM websub_subscriptions
+WHERE id = $1;
+
+-- name: GetWebSubSubscriptionForFeed :one
+SELECT *
+FROM websub_subscriptions
+WHERE feed_id = $1;
+
+-- name: GetWebSubSubscriptionsToRenew :many
+SELECT *
+FROM websub_subscriptions
+WHERE state = 'new'
+   OR (state = 'pending' AND updated_at <= CURRENT_TIMESTAMP - (sqlc.arg('retry_seconds')::int * INTERVAL '1 second'))
+   OR (state = 'active' AND lease_expires_at <= CURRENT_TIMESTAMP + (sqlc.arg('renew_seconds')::int * INTERVAL '1 second'))
+ORDER BY updated_at;
+
+-- name: SetWebSubSubscriptionState :exec
+UPDATE websub_subscriptions
+SET state = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: ActivateWebSubSubscription :exec
+UPDATE websub_subscriptions
+SET state = 'active',
+    lease_expires_at = CURRENT_TIMESTAMP + (sqlc.arg('lease_seconds')::int * INTERVAL '1 second'),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg('id');
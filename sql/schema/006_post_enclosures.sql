@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE post_enclosures (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    post_id INT NOT NULL,
+    url TEXT NOT NULL,
+    length BIGINT,
+    mime_type TEXT,
+    duration_seconds INT,
+    episode INT,
+    season INT,
+    image_url TEXT,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    UNIQUE(post_id, url)
+);
+
+-- +goose Down
+DROP TABLE post_enclosures;
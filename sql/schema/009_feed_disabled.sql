@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE feeds
+ADD COLUMN disabled_at TIMESTAMP NULL,
+ADD COLUMN disabled_reason TEXT NULL;
+
+-- +goose Down
+ALTER TABLE feeds
+DROP COLUMN disabled_at,
+DROP COLUMN disabled_reason;
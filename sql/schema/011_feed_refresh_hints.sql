@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE feeds
+ADD COLUMN min_refresh_seconds INT NULL,
+ADD COLUMN skip_hours INT NOT NULL DEFAULT 0,
+ADD COLUMN skip_days INT NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE feeds
+DROP COLUMN min_refresh_seconds,
+DROP COLUMN skip_hours,
+DROP COLUMN skip_days;
@@ -0,0 +1,17 @@
+-- +goose Up
+ALTER TABLE users
+ADD COLUMN deactivated_at TIMESTAMP NULL;
+
+-- Deleting a user must decide what happens to the feeds they added,
+-- other users may still follow them.
+ALTER TABLE feeds
+DROP CONSTRAINT fk_user,
+ADD CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT;
+
+-- +goose Down
+ALTER TABLE feeds
+DROP CONSTRAINT fk_user,
+ADD CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE users
+DROP COLUMN deactivated_at;
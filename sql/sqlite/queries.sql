@@ -0,0 +1,86 @@
+-- SQLite versions of the queries in sql/queries that use Postgres-only
+-- syntax. Every other query runs unchanged apart from its placeholders
+-- being rewritten from $1 to ?1.
+
+-- name: CreateFeedFollow :one
+INSERT INTO feed_follows (created_at, updated_at, user_id, feed_id)
+VALUES (?1, ?2, ?3, ?4)
+RETURNING
+    id, created_at, updated_at, user_id, feed_id,
+    (SELECT name FROM feeds WHERE feeds.id = feed_id) AS feed_name,
+    (SELECT name FROM users WHERE users.id = user_id) AS user_name;
+
+-- name: DeleteFeedFollowsForUser :exec
+DELETE FROM feed_follows
+WHERE user_id = ?1
+  AND feed_id IN (SELECT id FROM feeds WHERE url = ?2);
+
+-- name: GetNextFeedsToFetch :many
+SELECT id, name, url, user_id, created_at, updated_at, last_fetched_at, disabled_at, disabled_reason, next_fetch_at, min_refresh_seconds, skip_hours, skip_days
+FROM feeds
+WHERE disabled_at IS NULL
+  AND (next_fetch_at IS NULL OR next_fetch_at <= CURRENT_TIMESTAMP)
+  AND (last_fetched_at IS NULL
+       OR min_refresh_seconds IS NULL
+       OR datetime(last_fetched_at, min_refresh_seconds || ' seconds') <= CURRENT_TIMESTAMP)
+  AND (skip_hours & ?1) = 0
+  AND (skip_days & ?2) = 0
+ORDER BY next_fetch_at NULLS FIRST, last_fetched_at NULLS FIRST
+LIMIT ?3;
+
+-- name: PostponeFeedFetch :exec
+UPDATE feeds
+SET next_fetch_at = datetime('now', ?1 || ' seconds'),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ?2;
+
+-- name: NotifyFeedAdded :exec
+SELECT ?1;
+
+-- name: GetPostsForUser :many
+SELECT p.id, p.created_at, p.updated_at, p.title, p.url, p.description, p.published_at, p.feed_id, p.author, f.name as feed_name
+FROM posts p
+JOIN feeds f ON p.feed_id = f.id
+JOIN feed_follows ff ON f.id = ff.feed_id
+WHERE ff.user_id = ?1
+  AND (?2 IS NULL OR p.author LIKE '%' || ?2 || '%')
+  AND (?3 IS NULL OR EXISTS (
+    SELECT 1
+    FROM post_categories pc
+    JOIN categories c ON pc.category_id = c.id
+    WHERE pc.post_id = p.id
+      AND c.name = ?3
+  ))
+ORDER BY p.published_at DESC
+LIMIT ?4;
+
+-- name: CreateSession :exec
+INSERT INTO sessions (token_hash, user_id, expires_at)
+VALUES (
+    ?1,
+    ?2,
+    datetime('now', ?3 || ' seconds')
+);
+
+-- name: GetWebSubSubscriptionsToRenew :many
+SELECT id, created_at, updated_at, feed_id, hub_url, topic_url, secret, state, lease_expires_at
+FROM websub_subscriptions
+WHERE state = 'new'
+   OR (state = 'pending' AND updated_at <= datetime('now', '-' || ?1 || ' seconds'))
+   OR (state = 'active' AND lease_expires_at <= datetime('now', ?2 || ' seconds'))
+ORDER BY updated_at;
+
+-- name: ActivateWebSubSubscription :exec
+UPDATE websub_subscriptions
+SET state = 'active',
+    lease_expires_at = datetime('now', ?1 || ' seconds'),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ?2;
+
+-- name: ResetTables :exec
+DELETE FROM feeds;
+DELETE FROM users;
+DELETE FROM categories;
+
+-- name: SyncSequences :exec
+SELECT 1;
@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE post_enclosures (
+    id INTEGER PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    post_id INTEGER NOT NULL,
+    url TEXT NOT NULL,
+    length INTEGER,
+    mime_type TEXT,
+    duration_seconds INTEGER,
+    episode INTEGER,
+    season INTEGER,
+    image_url TEXT,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    UNIQUE(post_id, url)
+);
+
+-- +goose Down
+DROP TABLE post_enclosures;
@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE downloads (
+    id INTEGER PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    user_id TEXT NOT NULL,
+    enclosure_id INTEGER NOT NULL,
+    path TEXT NOT NULL,
+    size INTEGER NOT NULL,
+    sha256 TEXT NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (enclosure_id) REFERENCES post_enclosures(id) ON DELETE CASCADE,
+    UNIQUE(user_id, enclosure_id)
+);
+
+-- +goose Down
+DROP TABLE downloads;
@@ -0,0 +1,23 @@
+-- +goose Up
+ALTER TABLE posts
+ADD COLUMN author TEXT NULL;
+
+CREATE TABLE categories (
+    id INTEGER PRIMARY KEY,
+    name TEXT UNIQUE NOT NULL
+);
+
+CREATE TABLE post_categories (
+    post_id INTEGER NOT NULL,
+    category_id INTEGER NOT NULL,
+    PRIMARY KEY (post_id, category_id),
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE post_categories;
+DROP TABLE categories;
+
+ALTER TABLE posts
+DROP COLUMN author;
@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE feeds
+ADD COLUMN disabled_at TIMESTAMP NULL;
+
+ALTER TABLE feeds
+ADD COLUMN disabled_reason TEXT NULL;
+
+-- +goose Down
+ALTER TABLE feeds
+DROP COLUMN disabled_reason;
+
+ALTER TABLE feeds
+DROP COLUMN disabled_at;
@@ -0,0 +1,19 @@
+-- +goose Up
+ALTER TABLE feeds
+ADD COLUMN min_refresh_seconds INTEGER NULL;
+
+ALTER TABLE feeds
+ADD COLUMN skip_hours INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE feeds
+ADD COLUMN skip_days INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE feeds
+DROP COLUMN skip_days;
+
+ALTER TABLE feeds
+DROP COLUMN skip_hours;
+
+ALTER TABLE feeds
+DROP COLUMN min_refresh_seconds;
@@ -0,0 +1,12 @@
+-- +goose Up
+ALTER TABLE users
+ADD COLUMN role TEXT NOT NULL DEFAULT 'member' CHECK (role IN ('admin', 'member'));
+
+-- Existing databases get the earliest registered user as their admin.
+UPDATE users
+SET role = 'admin'
+WHERE id = (SELECT id FROM users ORDER BY created_at NULLS LAST LIMIT 1);
+
+-- +goose Down
+ALTER TABLE users
+DROP COLUMN role;
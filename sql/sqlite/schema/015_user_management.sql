@@ -0,0 +1,22 @@
+-- +goose Up
+ALTER TABLE users
+ADD COLUMN deactivated_at TIMESTAMP NULL;
+
+-- Deleting a user must decide what happens to the feeds they added,
+-- other users may still follow them. SQLite can't alter the foreign key
+-- of feeds.user_id to ON DELETE RESTRICT, so a trigger refuses instead;
+-- it runs before the cascade would.
+-- +goose StatementBegin
+CREATE TRIGGER users_restrict_feeds
+BEFORE DELETE ON users
+WHEN EXISTS (SELECT 1 FROM feeds WHERE user_id = OLD.id)
+BEGIN
+    SELECT RAISE(ABORT, 'FOREIGN KEY constraint failed');
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER users_restrict_feeds;
+
+ALTER TABLE users
+DROP COLUMN deactivated_at;
@@ -0,0 +1,21 @@
+// Package sqlite embeds the SQLite schema and the SQLite versions of
+// queries whose Postgres syntax SQLite doesn't understand.
+package sqlite
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed schema/*.sql
+var schemaFS embed.FS
+
+// Schema holds the goose migrations for SQLite databases. They mirror
+// sql/schema one for one, so both databases share version numbers and
+// their tables have the same columns in the same order.
+var Schema, _ = fs.Sub(schemaFS, "schema")
+
+// Queries holds the query overrides, in sqlc's "-- name:" format.
+//
+//go:embed queries.sql
+var Queries string